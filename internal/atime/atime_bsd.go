@@ -0,0 +1,17 @@
+//go:build darwin || freebsd || netbsd
+
+package atime
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// Of 返回文件的最后访问时间，无法获取时返回修改时间
+func Of(info fs.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec))
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || openbsd || dragonfly || darwin || freebsd || netbsd || windows)
+
+package atime
+
+import (
+	"io/fs"
+	"time"
+)
+
+// Of 当前平台无法获取访问时间，返回修改时间
+func Of(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
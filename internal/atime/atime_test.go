@@ -0,0 +1,66 @@
+package atime
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenKeepsAccessTime(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		read func(path string) error
+	}{
+		{"Open", func(path string) error {
+			file, err := Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			_, err = io.ReadAll(file)
+			return err
+		}},
+		{"ReadFile", func(path string) error {
+			_, err := ReadFile(path)
+			return err
+		}},
+		{"restore after a plain open", func(path string) error {
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			plain, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			file := &File{File: plain, info: info}
+			defer file.Close()
+			_, err = io.ReadAll(file)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "foo-1.0.jar")
+			if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.read(path); err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Of(info); !got.Equal(old) {
+				t.Errorf("access time after reading = %v, want %v", got, old)
+			}
+		})
+	}
+}
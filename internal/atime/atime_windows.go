@@ -0,0 +1,17 @@
+//go:build windows
+
+package atime
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// Of 返回文件的最后访问时间，无法获取时返回修改时间
+func Of(info fs.FileInfo) time.Time {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.LastAccessTime.Nanoseconds())
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,55 @@
+// Package atime 读取文件的访问时间，并在读取本地仓库中的文件时避免更新它
+//
+// 按使用时间清理（--unused-for、--max-size）依赖访问时间判断构件最后一次被构建读取的时间，
+// 扫描和检查时打开构件不能让它们看起来刚被使用过。
+package atime
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// File 通过 Open 打开的只读文件
+type File struct {
+	*os.File
+	info fs.FileInfo // 打开前的状态，不为 nil 时关闭后恢复访问时间
+}
+
+// Open 以只读方式打开文件，不更新文件的访问时间
+//
+// 支持 O_NOATIME 的平台上直接以该标志打开；不支持或没有权限（O_NOATIME 要求是文件所有者）时，
+// 关闭文件后把访问时间恢复为打开前的值，无法恢复时忽略。
+func Open(path string) (*File, error) {
+	if file, ok := openNoAtime(path); ok {
+		return &File{File: file}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{File: file, info: info}, nil
+}
+
+// Close 关闭文件，需要时恢复访问时间
+func (f *File) Close() error {
+	err := f.File.Close()
+	if f.info != nil {
+		os.Chtimes(f.Name(), Of(f.info), f.info.ModTime())
+	}
+	return err
+}
+
+// ReadFile 读取整个文件，不更新文件的访问时间
+func ReadFile(path string) ([]byte, error) {
+	file, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
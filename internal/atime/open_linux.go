@@ -0,0 +1,14 @@
+//go:build linux
+
+package atime
+
+import (
+	"os"
+	"syscall"
+)
+
+// openNoAtime 以 O_NOATIME 打开文件，失败时返回 false 由调用方退回普通打开
+func openNoAtime(path string) (*os.File, bool) {
+	file, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOATIME, 0)
+	return file, err == nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package atime
+
+import "os"
+
+// openNoAtime 当前平台不支持 O_NOATIME
+func openNoAtime(string) (*os.File, bool) {
+	return nil, false
+}
@@ -0,0 +1,125 @@
+// Package check 检查项目的依赖闭包能否只依靠本地仓库离线解析
+package check
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+	"github.com/lyj404/clean-mvn/internal/resolver"
+	"github.com/lyj404/clean-mvn/internal/scanner"
+)
+
+// Status 构件无法离线使用的原因
+type Status string
+
+const (
+	StatusMissing    Status = "missing"    // 本地仓库中没有该文件
+	StatusFailed     Status = "failed"     // 下载失败，本地只有 .lastUpdated 标记
+	StatusCorrupt    Status = "corrupt"    // 文件存在但无法解析
+	StatusUnresolved Status = "unresolved" // 无法确定版本或坐标
+)
+
+// Issue 闭包中一个无法离线使用的构件
+type Issue struct {
+	Artifact resolver.Artifact
+	Path     []string // 需要该构件的依赖路径
+	Status   Status
+	File     string // 缺失或损坏的文件，无法确定坐标时为空
+	Detail   string
+}
+
+// Report 离线完整性检查结果
+type Report struct {
+	Projects []resolver.Artifact
+	Checked  int // 检查的构件数
+	Issues   []Issue
+}
+
+// Complete 判断依赖闭包是否可以完全离线解析
+func (r *Report) Complete() bool {
+	return len(r.Issues) == 0
+}
+
+// Run 解析项目的依赖闭包，检查闭包中的每个 POM 和构件文件在本地仓库中是否存在且完好
+func Run(repo string, projects []string) (*Report, error) {
+	r := resolver.NewResolver(repo)
+	closure, err := r.Resolve(projects)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Projects: closure.Projects, Checked: len(closure.Nodes)}
+	reported := make(map[string]bool)
+
+	// 无法读取的 POM 和无法确定版本的依赖
+	for _, problem := range closure.Problems {
+		issue := Issue{Artifact: problem.Artifact, Path: problem.Path, Status: StatusUnresolved, Detail: problem.Reason}
+		if problem.NotFound {
+			issue.Status = StatusMissing
+		} else if problem.Artifact.Version != "" && !pom.IsVersionRange(problem.Artifact.Version) &&
+			!strings.Contains(problem.Artifact.Key(), "${") {
+			issue.File = r.PomPath(problem.Artifact)
+			issue.Status, issue.Detail = inspect(issue.File)
+			if issue.Status == "" {
+				issue.Status, issue.Detail = StatusUnresolved, problem.Reason
+			}
+		}
+		reported[problem.Artifact.Key()] = true
+		report.Issues = append(report.Issues, issue)
+	}
+
+	// POM 可用的构件还需要主构件文件
+	for _, node := range closure.Nodes {
+		if node.Artifact.Extension == "pom" || reported[node.Artifact.Key()] {
+			continue
+		}
+		file := r.ArtifactPath(node.Artifact)
+		status, detail := inspect(file)
+		if status == "" {
+			continue
+		}
+		report.Issues = append(report.Issues, Issue{
+			Artifact: node.Artifact,
+			Path:     node.Path,
+			Status:   status,
+			File:     file,
+			Detail:   detail,
+		})
+	}
+	return report, nil
+}
+
+// inspect 检查文件是否可以离线使用，可用时返回空状态
+func inspect(path string) (Status, string) {
+	if _, err := os.Stat(path); err != nil {
+		if !os.IsNotExist(err) {
+			return StatusCorrupt, err.Error()
+		}
+		if failures, err := scanner.ReadFailureMarker(path + ".lastUpdated"); err == nil {
+			detail := "download failed"
+			for _, failure := range failures {
+				if failure.Error != "" {
+					detail = fmt.Sprintf("download from %s failed: %s", failure.Repository, failure.Error)
+					break
+				}
+			}
+			return StatusFailed, detail
+		}
+		return StatusMissing, filepath.Base(path) + " is not in the local repository"
+	}
+
+	switch {
+	case strings.HasSuffix(path, ".pom"):
+		if _, err := pom.ParseFile(path); err != nil {
+			return StatusCorrupt, fmt.Sprintf("malformed POM: %v", err)
+		}
+	case scanner.IsArchive(path):
+		if err := scanner.VerifyArchive(path); err != nil {
+			return StatusCorrupt, fmt.Sprintf("corrupt archive: %v", err)
+		}
+	}
+	return "", ""
+}
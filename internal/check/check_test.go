@@ -0,0 +1,194 @@
+package check
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeJar(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	w := zip.NewWriter(file)
+	entry, _ := w.Create("META-INF/MANIFEST.MF")
+	entry.Write([]byte("Manifest-Version: 1.0\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writePOM(t *testing.T, dir, artifactID, version, body string) {
+	t.Helper()
+	writeFile(t, filepath.Join(dir, artifactID+"-"+version+".pom"),
+		"<project><groupId>org.example</groupId><artifactId>"+artifactID+"</artifactId><version>"+version+"</version>"+body+"</project>")
+}
+
+func TestRun(t *testing.T) {
+	repo := t.TempDir()
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, "pom.xml"), `<project>
+  <groupId>com.acme</groupId><artifactId>app</artifactId><version>1.0</version><packaging>pom</packaging>
+  <properties><example.version>1.0</example.version></properties>
+  <dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>ok</artifactId><version>${example.version}</version></dependency>
+    <dependency><groupId>org.example</groupId><artifactId>absent</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.example</groupId><artifactId>broken</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.example</groupId><artifactId>unreachable</artifactId><version>1.0</version></dependency>
+  </dependencies>
+  <build><pluginManagement><plugins><plugin><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></plugin></plugins></pluginManagement></build>
+</project>`)
+
+	example := filepath.Join(repo, "org", "example")
+	writePOM(t, filepath.Join(example, "ok", "1.0"), "ok", "1.0",
+		`<dependencies><dependency><groupId>org.example</groupId><artifactId>deep</artifactId><version>2.0</version></dependency></dependencies>`)
+	writeJar(t, filepath.Join(example, "ok", "1.0", "ok-1.0.jar"))
+	writePOM(t, filepath.Join(example, "broken", "1.0"), "broken", "1.0", "")
+	writeFile(t, filepath.Join(example, "broken", "1.0", "broken-1.0.jar"), "<html>proxy login</html>")
+	writeFile(t, filepath.Join(example, "unreachable", "1.0", "unreachable-1.0.pom.lastUpdated"),
+		"https\\://repo.example.com/maven2/.error=Could not transfer artifact\n")
+	plugin := filepath.Join(repo, "org", "apache", "maven", "plugins", "maven-install-plugin", "3.1.1")
+	writeFile(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.pom"),
+		"<project><groupId>org.apache.maven.plugins</groupId><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></project>")
+	writeJar(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.jar"))
+
+	report, err := Run(repo, []string{project})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Complete() {
+		t.Fatal("Complete() = true, want false")
+	}
+
+	got := make(map[string]Issue)
+	for _, issue := range report.Issues {
+		got[issue.Artifact.Key()] = issue
+	}
+	tests := []struct {
+		key    string
+		status Status
+		path   []string
+	}{
+		{"org.example:absent:1.0", StatusMissing, []string{"com.acme:app:1.0"}},
+		{"org.example:broken:1.0", StatusCorrupt, []string{"com.acme:app:1.0"}},
+		{"org.example:unreachable:1.0", StatusFailed, []string{"com.acme:app:1.0"}},
+		{"org.example:deep:2.0", StatusMissing, []string{"com.acme:app:1.0", "org.example:ok:1.0"}},
+	}
+	for _, tt := range tests {
+		issue, ok := got[tt.key]
+		if !ok {
+			t.Errorf("no issue reported for %s", tt.key)
+			continue
+		}
+		if issue.Status != tt.status {
+			t.Errorf("%s Status = %s, want %s (%s)", tt.key, issue.Status, tt.status, issue.Detail)
+		}
+		if !slices.Equal(issue.Path, tt.path) {
+			t.Errorf("%s Path = %v, want %v", tt.key, issue.Path, tt.path)
+		}
+	}
+	if len(report.Issues) != len(tests) {
+		t.Errorf("Run() reported %d issues, want %d: %+v", len(report.Issues), len(tests), report.Issues)
+	}
+	if detail := got["org.example:unreachable:1.0"].Detail; detail != "download from https://repo.example.com/maven2/ failed: Could not transfer artifact" {
+		t.Errorf("failed download Detail = %q", detail)
+	}
+}
+
+func TestRunComplete(t *testing.T) {
+	repo := t.TempDir()
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, "pom.xml"), `<project>
+  <groupId>com.acme</groupId><artifactId>app</artifactId><version>1.0</version><packaging>pom</packaging>
+  <build><pluginManagement><plugins><plugin><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></plugin></plugins></pluginManagement></build>
+</project>`)
+	plugin := filepath.Join(repo, "org", "apache", "maven", "plugins", "maven-install-plugin", "3.1.1")
+	writeFile(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.pom"),
+		"<project><groupId>org.apache.maven.plugins</groupId><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></project>")
+	writeJar(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.jar"))
+
+	report, err := Run(repo, []string{project})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !report.Complete() {
+		t.Errorf("Complete() = false, issues: %+v", report.Issues)
+	}
+	if report.Checked != 1 {
+		t.Errorf("Checked = %d, want 1", report.Checked)
+	}
+}
+
+func TestRunVersionRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     Status // 为空时表示闭包完整
+	}{
+		{"version in range", []string{"1.5", "3.0"}, ""},
+		{"no version in range", []string{"3.0"}, StatusMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			project := t.TempDir()
+			writeFile(t, filepath.Join(project, "pom.xml"), `<project>
+  <groupId>com.acme</groupId><artifactId>app</artifactId><version>1.0</version><packaging>pom</packaging>
+  <dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>lib</artifactId><version>[1.0,2.0)</version></dependency>
+  </dependencies>
+  <build><pluginManagement><plugins><plugin><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></plugin></plugins></pluginManagement></build>
+</project>`)
+			plugin := filepath.Join(repo, "org", "apache", "maven", "plugins", "maven-install-plugin", "3.1.1")
+			writeFile(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.pom"),
+				"<project><groupId>org.apache.maven.plugins</groupId><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></project>")
+			writeJar(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.jar"))
+			for _, version := range tt.versions {
+				dir := filepath.Join(repo, "org", "example", "lib", version)
+				writePOM(t, dir, "lib", version, "")
+				if version == "1.5" {
+					writeJar(t, filepath.Join(dir, "lib-"+version+".jar"))
+				} else {
+					// 范围外的版本即使损坏也不应被检查
+					writeFile(t, filepath.Join(dir, "lib-"+version+".jar"), "<html>proxy login</html>")
+				}
+			}
+
+			report, err := Run(repo, []string{project})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if tt.want == "" {
+				if !report.Complete() {
+					t.Errorf("Complete() = false, issues: %+v", report.Issues)
+				}
+				return
+			}
+			if len(report.Issues) != 1 {
+				t.Fatalf("Run() reported %d issues, want 1: %+v", len(report.Issues), report.Issues)
+			}
+			issue := report.Issues[0]
+			if issue.Artifact.Key() != "org.example:lib:[1.0,2.0)" || issue.Status != tt.want {
+				t.Errorf("issue = %s %s (%s), want %s missing", issue.Artifact.Key(), issue.Status, issue.Detail, "org.example:lib:[1.0,2.0)")
+			}
+		})
+	}
+}
@@ -2,15 +2,32 @@ package cleaner
 
 import (
 	"os"
+	"path/filepath"
 
+	"github.com/lyj404/clean-mvn/internal/filter"
 	"github.com/lyj404/clean-mvn/internal/logger"
 	"github.com/lyj404/clean-mvn/internal/progress"
+	"github.com/lyj404/clean-mvn/internal/scanner"
+	"github.com/lyj404/clean-mvn/internal/trash"
 	"github.com/lyj404/clean-mvn/pkg/types"
 )
 
+// Mode 清理模式
+type Mode int
+
+const (
+	// ModeDirectory 删除扫描结果中的整个目录或列出的文件
+	ModeDirectory Mode = iota
+	// ModeMarkers 只删除下载失败标记文件，保留目录中已下载的构件，让 Maven 重新尝试下载
+	ModeMarkers
+)
+
 // Cleaner 清理器
 type Cleaner struct {
 	logger *logger.CustomLogger
+	mode   Mode
+	filter *filter.Filter
+	trash  *trash.Run
 }
 
 // NewCleaner 创建新的清理器
@@ -20,34 +37,189 @@ func NewCleaner(logger *logger.CustomLogger) *Cleaner {
 	}
 }
 
+// SetMode 设置清理模式
+func (c *Cleaner) SetMode(mode Mode) {
+	c.mode = mode
+}
+
+// SetFilter 设置坐标过滤器，作为最后一道保护，不删除过滤器不允许的内容
+func (c *Cleaner) SetFilter(f *filter.Filter) {
+	c.filter = f
+}
+
+// SetTrash 设置隔离批次，设置后结果被移入隔离区而不是直接删除，可以用 restore 命令恢复
+func (c *Cleaner) SetTrash(run *trash.Run) {
+	c.trash = run
+}
+
 // CleanResult 清理结果
 type CleanResult struct {
-	DeletedCount int
-	DeletedSize  int64
+	DeletedCount   int
+	DeletedSize    int64
+	MarkersRemoved int // 只删除标记模式下删除的标记文件和 resolver-status.properties 失败条目的数量
 }
 
-// CleanDirectories 删除指定的目录列表
+// CleanDirectories 删除指定的目录列表，文件级结果只删除其中列出的文件
+//
+// 在 ModeMarkers 模式下只删除每个结果中的失败标记文件，没有标记的结果会被跳过。
+// 设置了坐标过滤器时，包含过滤器不允许的内容的结果会被跳过；设置了隔离批次时，删除的内容被移入隔离区。
 func (c *Cleaner) CleanDirectories(results []types.Result) CleanResult {
 	totalToDelete := len(results)
 	var deletedCount int
 	var deletedSize int64
+	var markersRemoved int
 
-	c.logger.Info("Starting file deletion...")
+	if c.trash != nil {
+		c.logger.Info("Moving flagged files to trash run %s...", c.trash.Dir())
+	} else if c.mode == ModeMarkers {
+		c.logger.Info("Starting failure marker deletion...")
+	} else {
+		c.logger.Info("Starting file deletion...")
+	}
 
 	for i := 0; i < totalToDelete; i++ {
 		result := results[i]
-		if err := os.RemoveAll(result.Path); err != nil {
+		if !c.filter.AllowsResult(result) {
+			c.logger.Warning("Skipped '%s': excluded by --include/--exclude.", result.Path)
+		} else if c.mode == ModeMarkers {
+			removed, size := c.removeMarkers(result)
+			if removed > 0 {
+				deletedCount++
+				deletedSize += size
+				markersRemoved += removed
+			}
+		} else if err := c.remove(result); err != nil {
 			c.logger.Error("Failed to delete directory '%s': %v", result.Path, err)
 		} else {
 			deletedCount++
 			deletedSize += result.Size
 		}
 
-		progress.DrawProgressBar(totalToDelete, deletedCount, "Deleting", i == totalToDelete-1, true)
+		progress.DrawProgressBar(totalToDelete, i+1, "Deleting", i == totalToDelete-1, true)
 	}
 
 	return CleanResult{
-		DeletedCount: deletedCount,
-		DeletedSize:  deletedSize,
+		DeletedCount:   deletedCount,
+		DeletedSize:    deletedSize,
+		MarkersRemoved: markersRemoved,
+	}
+}
+
+// removeMarkers 删除结果中的失败标记文件，返回删除的数量和大小
+//
+// resolver-status.properties 只去掉记录了失败的条目，每条算作一个标记。
+func (c *Cleaner) removeMarkers(result types.Result) (int, int64) {
+	var removed int
+	var size int64
+	for _, marker := range result.Markers {
+		if filepath.Base(marker) == scanner.ResolverStatusFile {
+			entries, freed, err := c.clearResolverStatus(marker)
+			if err != nil && !os.IsNotExist(err) {
+				c.logger.Error("Failed to clear failures in '%s': %v", marker, err)
+			}
+			removed += entries
+			size += freed
+			continue
+		}
+
+		info, err := os.Stat(marker)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				c.logger.Error("Failed to delete marker '%s': %v", marker, err)
+			}
+			continue
+		}
+		if err := c.discard(marker); err != nil {
+			c.logger.Error("Failed to delete marker '%s': %v", marker, err)
+			continue
+		}
+		removed++
+		size += info.Size()
+	}
+	return removed, size
+}
+
+// remove 删除单个结果：文件级结果只删除列出的文件，否则删除整个目录
+func (c *Cleaner) remove(result types.Result) error {
+	if len(result.Files) == 0 {
+		if c.trash == nil {
+			return os.RemoveAll(result.Path)
+		}
+		if err := c.trash.Move(result.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	for _, file := range result.Files {
+		var err error
+		if filepath.Base(file) == scanner.ResolverStatusFile {
+			_, _, err = c.clearResolverStatus(file)
+		} else {
+			err = c.discard(file)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearResolverStatus 从 resolver-status.properties 中去掉记录了失败的条目，没有剩余条目时删除整个文件
+//
+// 返回去掉的失败记录数和释放的大小。设置了隔离批次时完整的原文件被移入隔离区，再在原位置写回剩余的条目，恢复时用原文件替换写回的文件。
+func (c *Cleaner) clearResolverStatus(path string) (int, int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	content, removed, err := scanner.StripResolverStatusFailures(path)
+	if err != nil || removed == 0 {
+		return 0, 0, err
+	}
+
+	if content == nil {
+		if err := c.discard(path); err != nil {
+			return 0, 0, err
+		}
+		return removed, info.Size(), nil
+	}
+	if c.trash != nil {
+		if err := c.trash.Replace(path); err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := rewrite(path, content, info.Mode().Perm()); err != nil {
+		return 0, 0, err
+	}
+	return removed, info.Size() - int64(len(content)), nil
+}
+
+// rewrite 用新内容替换文件，先写入同目录下的临时文件再重命名，避免写入中途失败时留下不完整的文件
+func rewrite(path string, content []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// discard 删除单个文件，设置了隔离批次时移入隔离区
+func (c *Cleaner) discard(path string) error {
+	if c.trash != nil {
+		return c.trash.Move(path)
 	}
+	return os.Remove(path)
 }
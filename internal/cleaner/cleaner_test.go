@@ -4,8 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/lyj404/clean-mvn/internal/filter"
 	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/internal/trash"
 	"github.com/lyj404/clean-mvn/pkg/types"
 )
 
@@ -89,3 +92,207 @@ func TestCleanResult(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanDirectoriesFiles(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "maven-metadata-central.xml.lastUpdated")
+	metadata := filepath.Join(dir, "maven-metadata-central.xml")
+	version := filepath.Join(dir, "1.0")
+	os.WriteFile(marker, []byte("test"), 0644)
+	os.WriteFile(metadata, []byte("test"), 0644)
+	os.Mkdir(version, 0755)
+
+	c := NewCleaner(logger)
+	result := c.CleanDirectories([]types.Result{{Path: dir, Files: []string{marker, metadata}}})
+
+	if result.DeletedCount != 1 {
+		t.Errorf("CleanDirectories() DeletedCount = %v, want 1", result.DeletedCount)
+	}
+	for _, file := range []string{marker, metadata} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("File %s was not deleted", file)
+		}
+	}
+	if _, err := os.Stat(version); err != nil {
+		t.Errorf("Sibling directory %s should be kept: %v", version, err)
+	}
+}
+
+func TestCleanDirectoriesMarkersOnly(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	dir := t.TempDir()
+	jar := filepath.Join(dir, "foo-1.0.jar")
+	marker := filepath.Join(dir, "foo-1.0-sources.jar.lastUpdated")
+	status := filepath.Join(dir, "resolver-status.properties")
+	os.WriteFile(jar, []byte("PK"), 0644)
+	os.WriteFile(marker, []byte("test"), 0644)
+	os.WriteFile(status, []byte("maven-metadata-central.xml/central.error=Could not transfer\n"), 0644)
+
+	c := NewCleaner(logger)
+	c.SetMode(ModeMarkers)
+	result := c.CleanDirectories([]types.Result{
+		{Path: dir, Markers: []string{marker, status}},
+		{Path: filepath.Join(dir, "no-markers")},
+	})
+
+	if result.MarkersRemoved != 2 {
+		t.Errorf("CleanDirectories() MarkersRemoved = %v, want 2", result.MarkersRemoved)
+	}
+	if result.DeletedCount != 1 {
+		t.Errorf("CleanDirectories() DeletedCount = %v, want 1", result.DeletedCount)
+	}
+	for _, file := range []string{marker, status} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("Marker %s was not deleted", file)
+		}
+	}
+	if _, err := os.Stat(jar); err != nil {
+		t.Errorf("Artifact %s should be kept: %v", jar, err)
+	}
+}
+
+func TestCleanDirectoriesFilter(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	root := t.TempDir()
+	plugin := filepath.Join(root, "org", "apache", "maven", "plugins", "maven-clean-plugin", "3.2.0")
+	lib := filepath.Join(root, "org", "example", "lib", "1.0")
+	for _, dir := range []string{plugin, lib} {
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "file.jar"), []byte("test"), 0644)
+	}
+
+	f, err := filter.New(nil, []string{"org.apache.maven.plugins:*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCleaner(logger)
+	c.SetFilter(f)
+	result := c.CleanDirectories([]types.Result{
+		{Root: root, Path: plugin, Scope: types.ScopeVersion},
+		{Root: root, Path: lib, Scope: types.ScopeVersion},
+	})
+
+	if result.DeletedCount != 1 {
+		t.Errorf("CleanDirectories() DeletedCount = %v, want 1", result.DeletedCount)
+	}
+	if _, err := os.Stat(plugin); err != nil {
+		t.Errorf("Excluded directory %s should be kept: %v", plugin, err)
+	}
+	if _, err := os.Stat(lib); !os.IsNotExist(err) {
+		t.Errorf("Directory %s was not deleted", lib)
+	}
+}
+
+func TestCleanDirectoriesTrash(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	root := t.TempDir()
+	version := filepath.Join(root, "org", "example", "foo", "1.0")
+	artifact := filepath.Join(root, "org", "example", "bar")
+	metadata := filepath.Join(artifact, "maven-metadata-central.xml")
+	os.MkdirAll(version, 0755)
+	os.MkdirAll(artifact, 0755)
+	os.WriteFile(filepath.Join(version, "foo-1.0.jar"), []byte("PK"), 0644)
+	os.WriteFile(metadata, []byte("test"), 0644)
+
+	trashDir := t.TempDir()
+	run, err := trash.Begin(trashDir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCleaner(logger)
+	c.SetTrash(run)
+	result := c.CleanDirectories([]types.Result{
+		{Path: version},
+		{Path: artifact, Files: []string{metadata}},
+	})
+
+	if result.DeletedCount != 2 {
+		t.Errorf("CleanDirectories() DeletedCount = %v, want 2", result.DeletedCount)
+	}
+	for _, path := range []string{version, metadata} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not moved to the trash", path)
+		}
+	}
+	if got := len(run.Manifest().Entries); got != 2 {
+		t.Fatalf("trash manifest has %d entries, want 2", got)
+	}
+
+	if _, err := trash.Restore(trashDir, run.ID(), nil); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	for _, path := range []string{filepath.Join(version, "foo-1.0.jar"), metadata} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s was not restored: %v", path, err)
+		}
+	}
+}
+
+func TestCleanResolverStatus(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	content := "#NOTE: This is a Maven Resolver internal implementation file\n" +
+		"maven-metadata-central.xml/central.error=Could not transfer\n" +
+		"maven-metadata-central.xml/central.lastUpdated=1700000000000\n" +
+		"maven-metadata-nexus.xml/nexus.lastUpdated=1700000000000\n" +
+		"maven-metadata-nexus.xml/snapshots.error=\n"
+	kept := "#NOTE: This is a Maven Resolver internal implementation file\n" +
+		"maven-metadata-nexus.xml/nexus.lastUpdated=1700000000000\n"
+
+	tests := []struct {
+		name  string
+		mode  Mode
+		trash bool
+	}{
+		{"markers", ModeMarkers, false},
+		{"directory", ModeDirectory, false},
+		{"markers with trash", ModeMarkers, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			status := filepath.Join(dir, "resolver-status.properties")
+			os.WriteFile(status, []byte(content), 0644)
+
+			c := NewCleaner(logger)
+			c.SetMode(tt.mode)
+			var run *trash.Run
+			if tt.trash {
+				var err error
+				if run, err = trash.Begin(t.TempDir(), time.Now()); err != nil {
+					t.Fatal(err)
+				}
+				c.SetTrash(run)
+			}
+			result := c.CleanDirectories([]types.Result{{Path: dir, Files: []string{status}, Markers: []string{status}}})
+
+			if tt.mode == ModeMarkers && result.MarkersRemoved != 2 {
+				t.Errorf("CleanDirectories() MarkersRemoved = %v, want 2", result.MarkersRemoved)
+			}
+			if data, err := os.ReadFile(status); err != nil || string(data) != kept {
+				t.Errorf("resolver-status.properties = %q, %v, want %q", data, err, kept)
+			}
+			if run != nil {
+				entries := run.Manifest().Entries
+				if len(entries) != 1 {
+					t.Fatalf("trash manifest has %d entries, want 1", len(entries))
+				}
+				if data, _ := os.ReadFile(filepath.Join(run.Dir(), entries[0].Stored)); string(data) != content {
+					t.Errorf("trash kept %q, want the original file", data)
+				}
+
+				if _, err := trash.Restore(filepath.Dir(run.Dir()), run.ID(), nil); err != nil {
+					t.Fatalf("Restore() error = %v", err)
+				}
+				if data, _ := os.ReadFile(status); string(data) != content {
+					t.Errorf("restored resolver-status.properties = %q, want the original file", data)
+				}
+			}
+		})
+	}
+}
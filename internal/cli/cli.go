@@ -2,25 +2,68 @@ package cli
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	// CommandCheck 检查项目依赖闭包能否离线解析的子命令
+	CommandCheck = "check"
+	// CommandRestore 把隔离区中的批次移回原始路径的子命令
+	CommandRestore = "restore"
+	// CommandPurgeTrash 清空隔离区中旧批次的子命令
+	CommandPurgeTrash = "purge-trash"
+)
+
+// commands 支持的子命令，未指定子命令时扫描并清理仓库
+var commands = []string{CommandCheck, CommandRestore, CommandPurgeTrash}
+
 // Config CLI 配置
 type Config struct {
-	Path    string // Maven 仓库路径
-	Force   bool   // 是否跳过确认
-	DryRun  bool   // 是否只预览不删除
-	Workers int    // 并发工作数
-	LogFile string // 日志文件路径
+	Command       string        // 子命令，为空时扫描并清理仓库
+	Paths         []string      // Maven 仓库路径，可包含通配符，见 ExpandPaths
+	Force         bool          // 是否跳过确认
+	DryRun        bool          // 是否只预览不删除
+	Workers       int           // 并发工作数
+	LogFile       string        // 日志文件路径
+	MarkersOnly   bool          // 是否只删除下载失败标记文件
+	Detectors     []string      // 启用的检测器名称
+	TempMinAge    time.Duration // 临时文件的最小存在时间
+	KeepSnapshots int           // 每个快照目录保留的带时间戳构建数
+	KeepReleases  int           // 每个构件保留的正式版本数
+	UnusedFor     time.Duration // 版本目录未被使用的最长时间
+	MaxSize       int64         // 仓库的目标大小（字节）
+	Projects      []string      // 项目目录或 POM 文件，只保留它们的依赖闭包
+	Impact        bool          // 是否分析损坏构件对其他本地构件的影响
+	Cascade       bool          // 是否同时删除依赖损坏构件的本地构件
+
+	Quarantine   bool          // 是否把结果移入隔离区而不是直接删除
+	TrashDir     string        // 隔离区目录，为空时使用默认目录
+	TrashRun     string        // restore 命令恢复的批次 id，为空时列出所有批次
+	RestorePaths []string      // restore 命令只恢复这些原始路径，为空时恢复整个批次
+	OlderThan    time.Duration // purge-trash 命令删除早于该时长的批次，未指定时为负数
+
+	IncludeInstalled bool     // 分离布局中是否也清理本地安装的构件
+	Includes         []string // 只扫描匹配这些坐标模式的构件
+	Excludes         []string // 不扫描也不删除匹配这些坐标模式的构件
 }
 
 // ParseConfig 解析命令行参数
 func ParseConfig() Config {
-	config := Config{}
+	config := Config{OlderThan: -1}
+	var detectors string
 
-	flag.StringVar(&config.Path, "path", "", "Maven 仓库路径")
-	flag.StringVar(&config.Path, "p", "", "Maven 仓库路径（简写）")
+	addPath := func(value string) error {
+		config.Paths = append(config.Paths, value)
+		return nil
+	}
+	flag.Func("path", "Maven 仓库、Gradle 或 Ivy 缓存路径，可重复指定，支持 /home/*/.m2/repository 形式的通配符", addPath)
+	flag.Func("p", "Maven 仓库、Gradle 或 Ivy 缓存路径（简写）", addPath)
 	flag.BoolVar(&config.Force, "force", false, "跳过确认提示")
 	flag.BoolVar(&config.Force, "f", false, "跳过确认提示（简写）")
 	flag.BoolVar(&config.DryRun, "dry-run", false, "预览模式，只显示将要删除的内容而不实际删除")
@@ -29,12 +72,97 @@ func ParseConfig() Config {
 	flag.IntVar(&config.Workers, "w", 0, "并发工作数（简写）")
 	flag.StringVar(&config.LogFile, "log", "", "日志文件路径")
 	flag.StringVar(&config.LogFile, "l", "", "日志文件路径（简写）")
+	flag.BoolVar(&config.MarkersOnly, "markers-only", false, "只删除 .lastUpdated 等下载失败标记文件，保留已下载的构件")
+	flag.BoolVar(&config.MarkersOnly, "m", false, "只删除下载失败标记文件（简写）")
+	flag.StringVar(&detectors, "detect", "lastupdated", "启用的检测器，逗号分隔")
+	flag.DurationVar(&config.TempMinAge, "temp-min-age", 24*time.Hour, "临时文件和 Gradle 缓存中空 SHA-1 目录的最小存在时间，更新的不会被删除")
+	flag.IntVar(&config.KeepSnapshots, "keep-snapshots", 0, "每个快照目录按分类器和扩展名保留最新的 N 个带时间戳构建")
+	flag.IntVar(&config.KeepReleases, "keep-releases", 0, "每个 groupId:artifactId 保留最新的 N 个正式版本")
+	flag.Func("unused-for", "删除超过该时长未被使用的版本目录，如 90d、2160h", func(value string) error {
+		duration, err := ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		config.UnusedFor = duration
+		return nil
+	})
+
+	flag.Func("max-size", "仓库的目标大小，如 20GB，超出时按最近最少使用的顺序删除版本目录", func(value string) error {
+		size, err := ParseSize(value)
+		if err != nil {
+			return err
+		}
+		config.MaxSize = size
+		return nil
+	})
+	flag.BoolVar(&config.Impact, "impact", false, "列出依赖每个损坏构件的本地构件")
+	flag.BoolVar(&config.Cascade, "cascade", false, "同时删除直接或间接依赖损坏构件的本地构件，让它们一起重新解析")
+	flag.BoolVar(&config.IncludeInstalled, "include-installed", false, "分离布局中也清理 installed/ 下本地安装的构件")
+	flag.Func("include", "只扫描匹配的构件，模式为 groupId[:artifactId[:version]]，支持通配符，可重复或用逗号分隔", func(value string) error {
+		config.Includes = append(config.Includes, splitList(value)...)
+		return nil
+	})
+	flag.Func("exclude", "保护匹配的构件，不扫描也不删除，模式同 --include", func(value string) error {
+		config.Excludes = append(config.Excludes, splitList(value)...)
+		return nil
+	})
+	flag.Func("project", "项目目录或 pom.xml，只保留其依赖闭包中的构件，可重复或用逗号分隔", func(value string) error {
+		config.Projects = append(config.Projects, splitList(value)...)
+		return nil
+	})
+
+	flag.BoolVar(&config.Quarantine, "quarantine", false, "把结果移入隔离区而不是直接删除，可用 restore 命令恢复")
+	flag.StringVar(&config.TrashDir, "trash-dir", "", "隔离区目录（默认：$CLEAN_MVN_TRASH 或 ~/.clean-mvn/trash）")
+	flag.Func("older-than", "purge-trash 命令删除早于该时长的隔离批次，如 30d，0 表示全部", func(value string) error {
+		duration, err := ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		config.OlderThan = duration
+		return nil
+	})
+
+	args := os.Args[1:]
+	if len(args) > 0 && slices.Contains(commands, args[0]) {
+		config.Command = args[0]
+		args = args[1:]
+	}
+	positional := parseInterspersed(flag.CommandLine, args)
 
-	flag.Parse()
+	config.Detectors = splitList(detectors)
+	switch config.Command {
+	case CommandCheck:
+		// check 命令的位置参数是项目目录
+		config.Projects = append(config.Projects, positional...)
+	case CommandRestore:
+		// restore 命令的位置参数是批次 id 和要恢复的原始路径
+		if len(positional) > 0 {
+			config.TrashRun, config.RestorePaths = positional[0], positional[1:]
+		}
+	}
 
 	return config
 }
 
+// parseInterspersed 解析选项并返回位置参数，选项可以出现在位置参数之间，如 "restore <run> --trash-dir <dir>"
+//
+// "--" 之后的参数都作为位置参数。
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		rest := flags.Args()
+		if len(rest) == 0 {
+			return positional
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
 // IsHelpRequested 检查是否请求帮助信息
 func IsHelpRequested() bool {
 	for _, arg := range os.Args {
@@ -47,38 +175,77 @@ func IsHelpRequested() bool {
 
 // ShowUsage 显示使用帮助
 func ShowUsage() {
-	println("Usage: clean-mvn [options]")
+	println("Usage: clean-mvn [command] [options]")
+	println()
+	println("Commands:")
+	println("  (none)                 扫描并清理 Maven 仓库")
+	println("  check [project...]     检查项目的依赖闭包能否只依靠本地仓库离线解析，不完整时以非零状态退出")
+	println("  restore [run [path...]]")
+	println("                         把隔离批次移回原始路径，可只恢复指定路径；不带参数时列出所有批次")
+	println("  purge-trash --older-than <d>")
+	println("                         删除早于该时长的隔离批次")
 	println()
 	println("Options:")
-	println("  -p, --path <path>      Maven 仓库路径")
+	println("  -p, --path <path>      Maven 仓库、Gradle（~/.gradle）或 Ivy（~/.ivy2）缓存路径，可重复指定，支持通配符，如 '/home/*/.m2/repository'")
 	println("  -f, --force            跳过确认提示")
 	println("  -d, --dry-run          预览模式，只显示将要删除的内容而不实际删除")
 	println("  -w, --workers <n>     并发工作数（默认：CPU 核心数）")
 	println("  -l, --log <file>       日志文件路径")
+	println("  -m, --markers-only     只删除 .lastUpdated 等下载失败标记文件，保留已下载的构件")
+	println("      --detect <list>    启用的检测器，逗号分隔（默认：lastupdated）")
+	println("                         可选：lastupdated, corrupt-jar, checksum, content, pom, temp, incomplete")
+	println("      --temp-min-age <d> 临时文件和 Gradle 空 SHA-1 目录的最小存在时间（默认：24h）")
+	println("      --keep-snapshots <n>")
+	println("                         每个快照目录按分类器和扩展名保留最新的 N 个带时间戳构建")
+	println("      --keep-releases <n>")
+	println("                         每个 groupId:artifactId 保留最新的 N 个正式版本")
+	println("      --unused-for <d>   删除超过该时长未被使用的版本目录，支持 d（天）和 w（周），如 90d")
+	println("      --max-size <size>  仓库的目标大小，如 20GB，超出时优先删除最久未使用的快照版本，再删除正式版本")
+	println("      --project <dir>    项目目录或 pom.xml，删除不在其依赖闭包中的版本目录，可重复指定")
+	println("      --impact           列出依赖每个损坏构件的本地构件")
+	println("      --cascade          同时删除直接或间接依赖损坏构件的本地构件，让它们一起重新解析")
+	println("      --include <pattern>")
+	println("                         只扫描匹配的构件，如 'com.mycorp.*:*'，可重复指定")
+	println("      --exclude <pattern>")
+	println("                         保护匹配的构件，不扫描也不删除，如 'org.apache.maven.plugins:*'")
+	println("      --include-installed")
+	println("                         Maven 3.9 分离布局中也清理 installed/ 下本地安装的构件（默认跳过）")
+	println("      --quarantine       把结果移入隔离区而不是直接删除，可用 restore 命令恢复")
+	println("      --trash-dir <dir>  隔离区目录（默认：~/.clean-mvn/trash）")
+	println("      --older-than <d>   purge-trash 命令删除早于该时长的隔离批次，如 30d，0 表示全部")
 	println("  -h, --help             显示此帮助信息")
 	println()
 	println("Environment Variables:")
 	println("  MAVEN_REPO_PATH        默认 Maven 仓库路径")
+	println("  MAVEN_ARGS, MAVEN_OPTS 未指定 MAVEN_REPO_PATH 时，读取其中的 -Dmaven.repo.local")
+	println("  MAVEN_HOME, M2_HOME    Maven 安装目录，用于查找全局 settings.xml")
+	println("  CLEAN_MVN_TRASH        默认隔离区目录")
 	println()
 	println("Examples:")
 	println("  clean-mvn --path ~/.m2/repository")
+	println("  clean-mvn -p /srv/ci/shared-m2 -p '/home/*/.m2/repository' --dry-run")
 	println("  clean-mvn -p ~/.m2/repository --force")
 	println("  clean-mvn -p ~/.m2/repository --dry-run")
 	println("  clean-mvn -p ~/.m2/repository --workers 4")
+	println("  clean-mvn -p ~/.m2/repository --markers-only")
+	println("  clean-mvn -p ~/.m2/repository --detect lastupdated,corrupt-jar")
+	println("  clean-mvn -p ~/.m2/repository --keep-snapshots 3")
+	println("  clean-mvn -p ~/.m2/repository --keep-releases 2 --dry-run")
+	println("  clean-mvn -p ~/.m2/repository --unused-for 90d --dry-run")
+	println("  clean-mvn -p ~/.m2/repository --max-size 20GB --dry-run")
+	println("  clean-mvn -p ~/.m2/repository --project ~/src/app --project ~/src/lib --dry-run")
+	println("  clean-mvn -p ~/.m2/repository --detect corrupt-jar,checksum --cascade --dry-run")
+	println("  clean-mvn -p ~/.m2/repository --include 'com.mycorp.*:*' --keep-releases 3 --dry-run")
+	println("  clean-mvn check ~/src/app")
+	println("  clean-mvn -p ~/.m2/repository --detect corrupt-jar --quarantine --force")
+	println("  clean-mvn restore 20260101-120000 ~/.m2/repository/org/example")
+	println("  clean-mvn purge-trash --older-than 30d")
 }
 
-// GetDefaultPath 获取默认的 Maven 仓库路径
+// GetDefaultPath 获取默认的 Maven 仓库路径，查找顺序见 DiscoverRepository
 func GetDefaultPath() string {
-	if path := os.Getenv("MAVEN_REPO_PATH"); path != "" {
-		return path
-	}
-
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-
-	return homeDir + "/.m2/repository"
+	path, _ := DiscoverRepository()
+	return path
 }
 
 // GetWorkersFromEnv 从环境变量获取并发工作数
@@ -90,3 +257,94 @@ func GetWorkersFromEnv() int {
 	}
 	return 0
 }
+
+// ParseDuration 解析时长，在 time.ParseDuration 的基础上支持 d（天）和 w（周）单位，如 "90d"、"2w"
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(value)
+}
+
+// sizeUnits 大小单位，按 1024 进制换算
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize 解析带单位的大小，如 "20GB"、"512M"、"1.5T"，单位不区分大小写，没有单位时按字节计算
+func ParseSize(value string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	unit := int64(1)
+	for _, u := range sizeUnits {
+		if rest, ok := strings.CutSuffix(number, u.suffix); ok {
+			number, unit = strings.TrimSpace(rest), u.bytes
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(n * float64(unit)), nil
+}
+
+// ExpandPaths 展开仓库路径中的通配符并去除重复的路径
+//
+// 不含通配符的路径原样保留，由调用方检查是否存在；含通配符的路径只保留匹配到的目录，没有匹配时返回错误。
+func ExpandPaths(patterns []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			add(pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+		}
+		var dirs int
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				add(match)
+				dirs++
+			}
+		}
+		if dirs == 0 {
+			return nil, fmt.Errorf("no repository matches %s", pattern)
+		}
+	}
+	return paths, nil
+}
+
+// splitList 拆分逗号分隔的列表，忽略空白项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -3,17 +3,38 @@ package cli
 import (
 	"flag"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestParseConfig(t *testing.T) {
 	tests := []struct {
 		name        string
 		args        []string
-		wantPath    string
+		wantPaths   []string
 		wantForce   bool
 		wantDryRun  bool
 		wantWorkers int
+		wantMarkers bool
+		wantDetect  []string
+		wantMinAge  time.Duration
+		wantKeep    int
+		wantRelease int
+		wantUnused  time.Duration
+		wantMaxSize int64
+		wantProject []string
+		wantCommand string
+		wantImpact  bool
+		wantCascade bool
+		wantInclude []string
+		wantExclude []string
+		wantTrash   bool
+		wantTrashTo string
+		wantRun     string
+		wantRestore []string
+		wantOlder   time.Duration
 	}{
 		{
 			name:        "default values",
@@ -23,14 +44,19 @@ func TestParseConfig(t *testing.T) {
 			wantWorkers: 0,
 		},
 		{
-			name:     "with path",
-			args:     []string{"--path", "/test/path"},
-			wantPath: "/test/path",
+			name:      "with path",
+			args:      []string{"--path", "/test/path"},
+			wantPaths: []string{"/test/path"},
 		},
 		{
-			name:     "with shorthand path",
-			args:     []string{"-p", "/test/path"},
-			wantPath: "/test/path",
+			name:      "with shorthand path",
+			args:      []string{"-p", "/test/path"},
+			wantPaths: []string{"/test/path"},
+		},
+		{
+			name:      "with repeated path",
+			args:      []string{"-p", "/srv/m2", "--path", "/home/*/.m2/repository"},
+			wantPaths: []string{"/srv/m2", "/home/*/.m2/repository"},
 		},
 		{
 			name:      "with force",
@@ -62,10 +88,109 @@ func TestParseConfig(t *testing.T) {
 			args:        []string{"-w", "8"},
 			wantWorkers: 8,
 		},
+		{
+			name:        "with markers only",
+			args:        []string{"--markers-only"},
+			wantMarkers: true,
+		},
+		{
+			name:        "with shorthand markers only",
+			args:        []string{"-m"},
+			wantMarkers: true,
+		},
+		{
+			name:       "with detectors",
+			args:       []string{"--detect", "lastupdated, corrupt-jar"},
+			wantDetect: []string{"lastupdated", "corrupt-jar"},
+		},
+		{
+			name:       "with temp min age",
+			args:       []string{"--temp-min-age", "2h"},
+			wantMinAge: 2 * time.Hour,
+		},
+		{
+			name:     "with keep snapshots",
+			args:     []string{"--keep-snapshots", "3"},
+			wantKeep: 3,
+		},
+		{
+			name:        "with keep releases",
+			args:        []string{"--keep-releases", "2"},
+			wantRelease: 2,
+		},
+		{
+			name:       "with unused for",
+			args:       []string{"--unused-for", "90d"},
+			wantUnused: 90 * 24 * time.Hour,
+		},
+		{
+			name:        "with max size",
+			args:        []string{"--max-size", "20GB"},
+			wantMaxSize: 20 << 30,
+		},
+		{
+			name:        "with projects",
+			args:        []string{"--project", "/src/app", "--project", "/src/lib,/src/tools"},
+			wantProject: []string{"/src/app", "/src/lib", "/src/tools"},
+		},
+		{
+			name:        "with impact and cascade",
+			args:        []string{"--impact", "--cascade"},
+			wantImpact:  true,
+			wantCascade: true,
+		},
+		{
+			name:        "with include and exclude",
+			args:        []string{"--include", "com.mycorp.*:*", "--exclude", "org.apache.maven.plugins:*,org.example:foo:1.*"},
+			wantInclude: []string{"com.mycorp.*:*"},
+			wantExclude: []string{"org.apache.maven.plugins:*", "org.example:foo:1.*"},
+		},
+		{
+			name:        "check command",
+			args:        []string{"check", "--path", "/test/repo", "/src/app", "/src/lib"},
+			wantPaths:   []string{"/test/repo"},
+			wantProject: []string{"/src/app", "/src/lib"},
+			wantCommand: CommandCheck,
+		},
+		{
+			name:        "quarantine",
+			args:        []string{"-p", "/test/repo", "--quarantine", "--trash-dir", "/tmp/trash"},
+			wantPaths:   []string{"/test/repo"},
+			wantTrash:   true,
+			wantTrashTo: "/tmp/trash",
+		},
+		{
+			name:        "restore command",
+			args:        []string{"restore", "--trash-dir", "/tmp/trash", "20260101-120000", "/test/repo/org/example"},
+			wantCommand: CommandRestore,
+			wantTrashTo: "/tmp/trash",
+			wantRun:     "20260101-120000",
+			wantRestore: []string{"/test/repo/org/example"},
+		},
+		{
+			name:        "options after positional arguments",
+			args:        []string{"check", "/src/app", "-p", "/test/repo", "/src/lib", "--", "-odd-dir"},
+			wantPaths:   []string{"/test/repo"},
+			wantProject: []string{"/src/app", "/src/lib", "-odd-dir"},
+			wantCommand: CommandCheck,
+		},
+		{
+			name:        "restore with options after the run id",
+			args:        []string{"restore", "20260101-120000", "--trash-dir", "/tmp/trash"},
+			wantCommand: CommandRestore,
+			wantTrashTo: "/tmp/trash",
+			wantRun:     "20260101-120000",
+		},
+		{
+			name:        "purge-trash command",
+			args:        []string{"purge-trash", "--older-than", "30d"},
+			wantCommand: CommandPurgeTrash,
+			wantOlder:   30 * 24 * time.Hour,
+		},
 		{
 			name:        "all options",
 			args:        []string{"-p", "/test/path", "-f", "-d", "-w", "4"},
-			wantPath:    "/test/path",
+			wantPaths:   []string{"/test/path"},
 			wantForce:   true,
 			wantDryRun:  true,
 			wantWorkers: 4,
@@ -86,8 +211,14 @@ func TestParseConfig(t *testing.T) {
 
 			config := ParseConfig()
 
-			if config.Path != tt.wantPath {
-				t.Errorf("ParseConfig().Path = %v, want %v", config.Path, tt.wantPath)
+			if !slices.Equal(config.Includes, tt.wantInclude) {
+				t.Errorf("ParseConfig().Includes = %v, want %v", config.Includes, tt.wantInclude)
+			}
+			if !slices.Equal(config.Excludes, tt.wantExclude) {
+				t.Errorf("ParseConfig().Excludes = %v, want %v", config.Excludes, tt.wantExclude)
+			}
+			if !slices.Equal(config.Paths, tt.wantPaths) {
+				t.Errorf("ParseConfig().Paths = %v, want %v", config.Paths, tt.wantPaths)
 			}
 			if config.Force != tt.wantForce {
 				t.Errorf("ParseConfig().Force = %v, want %v", config.Force, tt.wantForce)
@@ -98,6 +229,152 @@ func TestParseConfig(t *testing.T) {
 			if config.Workers != tt.wantWorkers {
 				t.Errorf("ParseConfig().Workers = %v, want %v", config.Workers, tt.wantWorkers)
 			}
+			if config.MarkersOnly != tt.wantMarkers {
+				t.Errorf("ParseConfig().MarkersOnly = %v, want %v", config.MarkersOnly, tt.wantMarkers)
+			}
+			wantDetect := tt.wantDetect
+			if wantDetect == nil {
+				wantDetect = []string{"lastupdated"}
+			}
+			if !slices.Equal(config.Detectors, wantDetect) {
+				t.Errorf("ParseConfig().Detectors = %v, want %v", config.Detectors, wantDetect)
+			}
+			wantMinAge := tt.wantMinAge
+			if wantMinAge == 0 {
+				wantMinAge = 24 * time.Hour
+			}
+			if config.TempMinAge != wantMinAge {
+				t.Errorf("ParseConfig().TempMinAge = %v, want %v", config.TempMinAge, wantMinAge)
+			}
+			if config.KeepSnapshots != tt.wantKeep {
+				t.Errorf("ParseConfig().KeepSnapshots = %v, want %v", config.KeepSnapshots, tt.wantKeep)
+			}
+			if config.KeepReleases != tt.wantRelease {
+				t.Errorf("ParseConfig().KeepReleases = %v, want %v", config.KeepReleases, tt.wantRelease)
+			}
+			if config.UnusedFor != tt.wantUnused {
+				t.Errorf("ParseConfig().UnusedFor = %v, want %v", config.UnusedFor, tt.wantUnused)
+			}
+			if config.MaxSize != tt.wantMaxSize {
+				t.Errorf("ParseConfig().MaxSize = %v, want %v", config.MaxSize, tt.wantMaxSize)
+			}
+			if config.Impact != tt.wantImpact || config.Cascade != tt.wantCascade {
+				t.Errorf("ParseConfig() Impact, Cascade = %v, %v, want %v, %v", config.Impact, config.Cascade, tt.wantImpact, tt.wantCascade)
+			}
+			if config.Command != tt.wantCommand {
+				t.Errorf("ParseConfig().Command = %q, want %q", config.Command, tt.wantCommand)
+			}
+			if !slices.Equal(config.Projects, tt.wantProject) {
+				t.Errorf("ParseConfig().Projects = %v, want %v", config.Projects, tt.wantProject)
+			}
+			if config.Quarantine != tt.wantTrash || config.TrashDir != tt.wantTrashTo {
+				t.Errorf("ParseConfig() Quarantine, TrashDir = %v, %q, want %v, %q", config.Quarantine, config.TrashDir, tt.wantTrash, tt.wantTrashTo)
+			}
+			if config.TrashRun != tt.wantRun || !slices.Equal(config.RestorePaths, tt.wantRestore) {
+				t.Errorf("ParseConfig() TrashRun, RestorePaths = %q, %v, want %q, %v", config.TrashRun, config.RestorePaths, tt.wantRun, tt.wantRestore)
+			}
+			wantOlder := tt.wantOlder
+			if wantOlder == 0 {
+				wantOlder = -1
+			}
+			if config.OlderThan != wantOlder {
+				t.Errorf("ParseConfig().OlderThan = %v, want %v", config.OlderThan, wantOlder)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90d", 90 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{" 7d ", 7 * 24 * time.Hour, false},
+		{"d", 0, true},
+		{"-1d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"20GB", 20 << 30, false},
+		{"20gb", 20 << 30, false},
+		{"512M", 512 << 20, false},
+		{"1.5T", 3 << 39, false},
+		{"100 KB", 100 << 10, false},
+		{"4096", 4096, false},
+		{"10B", 10, false},
+		{"GB", 0, true},
+		{"-1GB", 0, true},
+		{"lots", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPaths(t *testing.T) {
+	home := t.TempDir()
+	for _, user := range []string{"alice", "bob"} {
+		if err := os.MkdirAll(filepath.Join(home, user, ".m2", "repository"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alice := filepath.Join(home, "alice", ".m2", "repository")
+	bob := filepath.Join(home, "bob", ".m2", "repository")
+	pattern := filepath.Join(home, "*", ".m2", "repository")
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  bool
+	}{
+		{"plain path kept", []string{"/srv/m2"}, []string{filepath.Clean("/srv/m2")}, false},
+		{"glob expanded", []string{pattern}, []string{alice, bob}, false},
+		{"duplicates removed", []string{alice, pattern, alice + string(filepath.Separator)}, []string{alice, bob}, false},
+		{"glob without match", []string{filepath.Join(home, "*", "missing")}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPaths(tt.patterns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandPaths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExpandPaths() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// settings Maven settings.xml 中 clean-mvn 关心的部分
+type settings struct {
+	LocalRepository string `xml:"localRepository"`
+}
+
+// settingsPattern 匹配 settings.xml 中的 ${...} 引用
+var settingsPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// discovery 查找本地仓库路径时使用的环境，便于测试时替换
+type discovery struct {
+	getenv   func(string) string
+	home     string
+	workDir  string
+	lookPath func(string) (string, error)
+}
+
+// DiscoverRepository 按 Maven 的优先级查找本地仓库路径，返回路径及其来源
+//
+// 优先级从高到低：MAVEN_REPO_PATH 环境变量、.mvn/maven.config 和 MAVEN_ARGS 中的 -Dmaven.repo.local、
+// MAVEN_OPTS 中的 -Dmaven.repo.local、用户 settings.xml、全局 ${maven.home}/conf/settings.xml 中的
+// <localRepository>，最后是 ~/.m2/repository。
+func DiscoverRepository() (path, source string) {
+	homeDir, _ := os.UserHomeDir()
+	workDir, _ := os.Getwd()
+	d := discovery{getenv: os.Getenv, home: homeDir, workDir: workDir, lookPath: exec.LookPath}
+	return d.repository()
+}
+
+// repository 实现 DiscoverRepository
+func (d discovery) repository() (path, source string) {
+	if path := d.getenv("MAVEN_REPO_PATH"); path != "" {
+		return path, "MAVEN_REPO_PATH environment variable"
+	}
+
+	// maven.config 和 MAVEN_ARGS 都是命令行参数，后出现的覆盖先出现的
+	var args []argument
+	if config := d.mavenConfig(); config != "" {
+		if content, err := os.ReadFile(config); err == nil {
+			args = append(args, arguments(string(content), config)...)
+		}
+	}
+	args = append(args, arguments(d.getenv("MAVEN_ARGS"), "MAVEN_ARGS environment variable")...)
+
+	if value, from, ok := lastOption(args, "-Dmaven.repo.local"); ok {
+		return d.resolve(value, from), "-Dmaven.repo.local in " + from
+	}
+	// MAVEN_OPTS 中的系统属性在 JVM 启动时设置，会被命令行中的 -D 覆盖
+	opts := arguments(d.getenv("MAVEN_OPTS"), "MAVEN_OPTS environment variable")
+	if value, from, ok := lastOption(opts, "-Dmaven.repo.local"); ok {
+		return d.resolve(value, from), "-Dmaven.repo.local in " + from
+	}
+
+	userSettings := filepath.Join(d.home, ".m2", "settings.xml")
+	if value, from, ok := lastOption(args, "-s", "--settings"); ok {
+		userSettings = d.resolve(value, from)
+	}
+	if path, ok := d.localRepository(userSettings); ok {
+		return path, "<localRepository> in " + userSettings
+	}
+
+	globalSettings := ""
+	if value, from, ok := lastOption(args, "-gs", "--global-settings"); ok {
+		globalSettings = d.resolve(value, from)
+	} else if mavenHome := d.mavenHome(); mavenHome != "" {
+		globalSettings = filepath.Join(mavenHome, "conf", "settings.xml")
+	}
+	if globalSettings != "" {
+		if path, ok := d.localRepository(globalSettings); ok {
+			return path, "<localRepository> in " + globalSettings
+		}
+	}
+
+	if d.home == "" {
+		return "", ""
+	}
+	return filepath.Join(d.home, ".m2", "repository"), "default location"
+}
+
+// localRepository 读取 settings.xml 中的 <localRepository> 并展开其中的属性
+func (d discovery) localRepository(path string) (string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	var s settings
+	if err := xml.NewDecoder(file).Decode(&s); err != nil {
+		return "", false
+	}
+	repo := strings.TrimSpace(s.LocalRepository)
+	if repo == "" {
+		return "", false
+	}
+	return d.interpolate(repo), true
+}
+
+// interpolate 展开 ${user.home}、${env.X} 和 ${maven.home}，无法展开的引用原样保留
+func (d discovery) interpolate(value string) string {
+	return settingsPattern.ReplaceAllStringFunc(value, func(expr string) string {
+		name := expr[2 : len(expr)-1]
+		switch {
+		case name == "user.home" && d.home != "":
+			return d.home
+		case name == "maven.home":
+			if mavenHome := d.mavenHome(); mavenHome != "" {
+				return mavenHome
+			}
+		case strings.HasPrefix(name, "env."):
+			if env := d.getenv(strings.TrimPrefix(name, "env.")); env != "" {
+				return env
+			}
+		}
+		return expr
+	})
+}
+
+// resolve 展开属性并解析相对路径，来自 maven.config 的路径相对于项目根目录，其余相对于工作目录
+func (d discovery) resolve(path, source string) string {
+	path = d.interpolate(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	if filepath.Base(source) == "maven.config" {
+		return filepath.Join(filepath.Dir(filepath.Dir(source)), path)
+	}
+	return filepath.Join(d.workDir, path)
+}
+
+// mavenConfig 从工作目录向上查找 .mvn/maven.config
+func (d discovery) mavenConfig() string {
+	if d.workDir == "" {
+		return ""
+	}
+	for dir := d.workDir; ; dir = filepath.Dir(dir) {
+		config := filepath.Join(dir, ".mvn", "maven.config")
+		if _, err := os.Stat(config); err == nil {
+			return config
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// mavenHome 从 MAVEN_HOME、M2_HOME 或 PATH 中的 mvn 推断 Maven 安装目录
+func (d discovery) mavenHome() string {
+	for _, name := range []string{"MAVEN_HOME", "M2_HOME"} {
+		if home := d.getenv(name); home != "" {
+			return home
+		}
+	}
+	if d.lookPath == nil {
+		return ""
+	}
+	mvn, err := d.lookPath("mvn")
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(mvn); err == nil {
+		mvn = resolved
+	}
+	return filepath.Dir(filepath.Dir(mvn))
+}
+
+// argument 命令行参数及其来源
+type argument struct {
+	value  string
+	source string
+}
+
+// arguments 按空白拆分参数，支持单引号和双引号
+func arguments(line, source string) []argument {
+	var args []argument
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, c := range line {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(c)
+		case c == '"' || c == '\'':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, argument{value: current.String(), source: source})
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, argument{value: current.String(), source: source})
+	}
+	return args
+}
+
+// lastOption 返回最后一次出现的选项值，支持 "-Dname=value"、"-D name=value"、"-s value" 和 "--settings=value" 等形式
+func lastOption(args []argument, names ...string) (value, source string, ok bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i].value
+		for _, name := range names {
+			property, isProperty := strings.CutPrefix(name, "-D")
+			switch {
+			case isProperty && strings.HasPrefix(arg, name+"="):
+				value, source, ok = strings.TrimPrefix(arg, name+"="), args[i].source, true
+			case isProperty && (arg == "-D" || arg == "--define") && i+1 < len(args) &&
+				strings.HasPrefix(args[i+1].value, property+"="):
+				value, source, ok = strings.TrimPrefix(args[i+1].value, property+"="), args[i+1].source, true
+			case !isProperty && arg == name && i+1 < len(args):
+				value, source, ok = args[i+1].value, args[i+1].source, true
+			case !isProperty && strings.HasPrefix(arg, name+"="):
+				value, source, ok = strings.TrimPrefix(arg, name+"="), args[i].source, true
+			}
+		}
+	}
+	return value, source, ok
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSettingsFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverRepository(t *testing.T) {
+	settingsXML := func(repo string) string {
+		return `<?xml version="1.0"?><settings><localRepository>` + repo + `</localRepository></settings>`
+	}
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		files      map[string]string // 相对于临时目录，home/ 为用户目录，project/ 为工作目录，maven/ 为 Maven 安装目录
+		wantPath   string            // 相对于临时目录
+		wantSource string            // 来源中应包含的内容
+	}{
+		{
+			name:       "default location",
+			wantPath:   "home/.m2/repository",
+			wantSource: "default location",
+		},
+		{
+			name:       "MAVEN_REPO_PATH wins",
+			env:        map[string]string{"MAVEN_REPO_PATH": "{tmp}/env-repo", "MAVEN_OPTS": "-Dmaven.repo.local={tmp}/opts"},
+			wantPath:   "env-repo",
+			wantSource: "MAVEN_REPO_PATH",
+		},
+		{
+			name: "maven.config overrides MAVEN_OPTS and settings",
+			env:  map[string]string{"MAVEN_OPTS": "-Dmaven.repo.local={tmp}/opts"},
+			files: map[string]string{
+				"project/.mvn/maven.config": "-T 4\n-Dmaven.repo.local=${user.home}/config-repo\n",
+				"home/.m2/settings.xml":     settingsXML("{tmp}/user"),
+			},
+			wantPath:   "home/config-repo",
+			wantSource: "maven.config",
+		},
+		{
+			name:       "MAVEN_ARGS overrides maven.config",
+			env:        map[string]string{"MAVEN_ARGS": "-D maven.repo.local={tmp}/args"},
+			files:      map[string]string{"project/.mvn/maven.config": "-Dmaven.repo.local={tmp}/config"},
+			wantPath:   "args",
+			wantSource: "MAVEN_ARGS",
+		},
+		{
+			name:       "MAVEN_OPTS overrides settings",
+			env:        map[string]string{"MAVEN_OPTS": `-Xmx1g "-Dmaven.repo.local={tmp}/opts repo"`},
+			files:      map[string]string{"home/.m2/settings.xml": settingsXML("{tmp}/user")},
+			wantPath:   "opts repo",
+			wantSource: "MAVEN_OPTS",
+		},
+		{
+			name:       "relative path in maven.config resolves against the project",
+			files:      map[string]string{"project/.mvn/maven.config": "-Dmaven.repo.local=.m2/repository"},
+			wantPath:   "project/.m2/repository",
+			wantSource: "maven.config",
+		},
+		{
+			name:       "relative path in MAVEN_ARGS resolves against the working directory",
+			env:        map[string]string{"MAVEN_ARGS": "-Dmaven.repo.local=args-repo"},
+			wantPath:   "project/module/args-repo",
+			wantSource: "MAVEN_ARGS",
+		},
+		{
+			name:       "relative path in MAVEN_OPTS resolves against the working directory",
+			env:        map[string]string{"MAVEN_OPTS": "-Dmaven.repo.local=../opts-repo"},
+			wantPath:   "project/opts-repo",
+			wantSource: "MAVEN_OPTS",
+		},
+		{
+			name:       "user settings with env interpolation",
+			env:        map[string]string{"REPO_ROOT": "{tmp}/roots", "MAVEN_HOME": "{tmp}/maven"},
+			files:      map[string]string{"home/.m2/settings.xml": settingsXML("${env.REPO_ROOT}/m2"), "maven/conf/settings.xml": settingsXML("{tmp}/global")},
+			wantPath:   "roots/m2",
+			wantSource: filepath.Join("home", ".m2", "settings.xml"),
+		},
+		{
+			name:       "settings file given in maven.config",
+			files:      map[string]string{"project/.mvn/maven.config": "--settings=custom.xml", "project/custom.xml": settingsXML("{tmp}/custom")},
+			wantPath:   "custom",
+			wantSource: "custom.xml",
+		},
+		{
+			name:       "global settings from MAVEN_HOME",
+			env:        map[string]string{"MAVEN_HOME": "{tmp}/maven"},
+			files:      map[string]string{"home/.m2/settings.xml": "<settings></settings>", "maven/conf/settings.xml": settingsXML("${maven.home}/repo")},
+			wantPath:   "maven/repo",
+			wantSource: filepath.Join("maven", "conf", "settings.xml"),
+		},
+		{
+			name:       "invalid user settings fall through",
+			files:      map[string]string{"home/.m2/settings.xml": "<settings><localRepository>"},
+			wantPath:   "home/.m2/repository",
+			wantSource: "default location",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			expand := func(s string) string { return strings.ReplaceAll(s, "{tmp}", tmp) }
+			for name, content := range tt.files {
+				writeSettingsFile(t, filepath.Join(tmp, name), expand(content))
+			}
+			workDir := filepath.Join(tmp, "project", "module")
+			if err := os.MkdirAll(workDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			d := discovery{
+				getenv:   func(name string) string { return expand(tt.env[name]) },
+				home:     filepath.Join(tmp, "home"),
+				workDir:  workDir,
+				lookPath: func(string) (string, error) { return "", errors.New("not found") },
+			}
+			path, source := d.repository()
+			if want := filepath.Join(tmp, tt.wantPath); path != want {
+				t.Errorf("repository() path = %s, want %s", path, want)
+			}
+			if !strings.Contains(source, tt.wantSource) {
+				t.Errorf("repository() source = %q, want it to contain %q", source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestMavenHomeFromPath(t *testing.T) {
+	tmp := t.TempDir()
+	mvn := filepath.Join(tmp, "apache-maven-3.9.6", "bin", "mvn")
+	writeSettingsFile(t, mvn, "")
+
+	d := discovery{
+		getenv:   func(string) string { return "" },
+		lookPath: func(string) (string, error) { return mvn, nil },
+	}
+	if got, want := d.mavenHome(), filepath.Join(tmp, "apache-maven-3.9.6"); got != want {
+		t.Errorf("mavenHome() = %s, want %s", got, want)
+	}
+}
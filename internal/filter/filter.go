@@ -0,0 +1,251 @@
+// Package filter 按 Maven 坐标限定或保护仓库中的部分构件
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// Pattern groupId:artifactId:version 形式的坐标模式
+//
+// 每部分支持 *、? 和 [...] 通配符，* 可以匹配 groupId 中的点；省略的部分匹配任意值，
+// 例如 "com.mycorp.*" 等同于 "com.mycorp.*:*:*"。
+type Pattern struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+}
+
+// ParsePattern 解析坐标模式
+func ParsePattern(value string) (Pattern, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) > 3 || parts[0] == "" {
+		return Pattern{}, fmt.Errorf("invalid coordinate pattern %q, want groupId[:artifactId[:version]]", value)
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "*")
+	}
+	for _, part := range parts {
+		if part == "" {
+			return Pattern{}, fmt.Errorf("invalid coordinate pattern %q: empty part", value)
+		}
+		if _, err := path.Match(part, ""); err != nil {
+			return Pattern{}, fmt.Errorf("invalid coordinate pattern %q: %w", value, err)
+		}
+	}
+	return Pattern{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2]}, nil
+}
+
+// String 返回 groupId:artifactId:version 形式的模式
+func (p Pattern) String() string {
+	return p.GroupID + ":" + p.ArtifactID + ":" + p.Version
+}
+
+// Matches 判断坐标是否匹配模式
+func (p Pattern) Matches(groupID, artifactID, version string) bool {
+	return match(p.GroupID, groupID) && match(p.ArtifactID, artifactID) && match(p.Version, version)
+}
+
+// Filter 包含和排除模式的组合：坐标需匹配任一包含模式（未设置时匹配所有坐标），且不匹配任何排除模式
+//
+// nil 的 Filter 允许所有坐标。
+type Filter struct {
+	includes []Pattern
+	excludes []Pattern
+}
+
+// New 解析包含和排除模式，两者都为空时返回 nil
+func New(includes, excludes []string) (*Filter, error) {
+	if len(includes) == 0 && len(excludes) == 0 {
+		return nil, nil
+	}
+	f := &Filter{}
+	for _, value := range includes {
+		pattern, err := ParsePattern(value)
+		if err != nil {
+			return nil, err
+		}
+		f.includes = append(f.includes, pattern)
+	}
+	for _, value := range excludes {
+		pattern, err := ParsePattern(value)
+		if err != nil {
+			return nil, err
+		}
+		f.excludes = append(f.excludes, pattern)
+	}
+	return f, nil
+}
+
+// Allows 判断版本是否被允许
+func (f *Filter) Allows(groupID, artifactID, version string) bool {
+	if f == nil {
+		return true
+	}
+	included := len(f.includes) == 0 || slices.ContainsFunc(f.includes, func(p Pattern) bool {
+		return p.Matches(groupID, artifactID, version)
+	})
+	return included && !slices.ContainsFunc(f.excludes, func(p Pattern) bool {
+		return p.Matches(groupID, artifactID, version)
+	})
+}
+
+// AllowsArtifact 判断构件是否可能有被允许的版本，返回 false 时可以跳过整个 artifactId 目录
+func (f *Filter) AllowsArtifact(groupID, artifactID string) bool {
+	if f == nil {
+		return true
+	}
+	included := len(f.includes) == 0 || slices.ContainsFunc(f.includes, func(p Pattern) bool {
+		return match(p.GroupID, groupID) && match(p.ArtifactID, artifactID)
+	})
+	return included && !slices.ContainsFunc(f.excludes, func(p Pattern) bool {
+		return match(p.GroupID, groupID) && match(p.ArtifactID, artifactID) && p.Version == "*"
+	})
+}
+
+// AllowsGroup 判断 groupId 目录下是否可能有被允许的坐标，返回 false 时可以跳过整个目录
+//
+// 目录下的坐标的 groupId 为 prefix 本身或以 "prefix." 开头。
+func (f *Filter) AllowsGroup(prefix string) bool {
+	if f == nil {
+		return true
+	}
+	included := len(f.includes) == 0 || slices.ContainsFunc(f.includes, func(p Pattern) bool {
+		return mayMatchUnder(p.GroupID, prefix)
+	})
+	return included && !slices.ContainsFunc(f.excludes, func(p Pattern) bool {
+		return p.ArtifactID == "*" && p.Version == "*" && matchesAllUnder(p.GroupID, prefix)
+	})
+}
+
+// AllowsWholeGroup 判断 groupId 目录下的所有坐标是否都被允许，用于确认能否删除整个目录
+func (f *Filter) AllowsWholeGroup(prefix string) bool {
+	if f == nil {
+		return true
+	}
+	included := len(f.includes) == 0 || slices.ContainsFunc(f.includes, func(p Pattern) bool {
+		return p.ArtifactID == "*" && p.Version == "*" && matchesAllUnder(p.GroupID, prefix)
+	})
+	return included && !slices.ContainsFunc(f.excludes, func(p Pattern) bool {
+		return mayMatchUnder(p.GroupID, prefix)
+	})
+}
+
+// AllowsDir 判断遍历仓库时是否需要进入目录，scope 为目录所在的仓库层级
+//
+// Gradle 模块缓存的 group 为单层目录，按相同的规则处理；ScopeFile 的 SHA-1 目录按其所在的版本判断。
+func (f *Filter) AllowsDir(root, dir string, scope types.Scope) bool {
+	if f == nil {
+		return true
+	}
+	segments, ok := segmentsOf(root, dir)
+	if !ok {
+		return true
+	}
+	n := len(segments)
+	switch {
+	case scope == types.ScopeFile && n >= 4:
+		return f.Allows(strings.Join(segments[:n-3], "."), segments[n-3], segments[n-2])
+	case scope == types.ScopeVersion && n >= 3:
+		return f.Allows(strings.Join(segments[:n-2], "."), segments[n-2], segments[n-1])
+	case scope == types.ScopeArtifact && n >= 2:
+		return f.AllowsArtifact(strings.Join(segments[:n-1], "."), segments[n-1])
+	default:
+		return f.AllowsGroup(strings.Join(segments, "."))
+	}
+}
+
+// AllowsResult 判断结果中将要删除的所有内容是否都被允许
+//
+// artifactId 目录按其中实际存在的版本目录逐一判断；groupId 目录及无法确定坐标的结果要求所有坐标都被允许。
+func (f *Filter) AllowsResult(res types.Result) bool {
+	if f == nil {
+		return true
+	}
+	if res.Root == "" {
+		return false
+	}
+	segments, ok := segmentsOf(res.Root, res.Path)
+	if !ok {
+		return false
+	}
+	n := len(segments)
+	switch {
+	case res.Scope == types.ScopeFile && n >= 4:
+		return f.Allows(strings.Join(segments[:n-3], "."), segments[n-3], segments[n-2])
+	case res.Scope == types.ScopeVersion && n >= 3:
+		return f.Allows(strings.Join(segments[:n-2], "."), segments[n-2], segments[n-1])
+	case res.Scope == types.ScopeArtifact && len(res.Files) > 0 && res.Coordinates.Version != "":
+		// Ivy 缓存中只删除模块目录中某个版本的文件
+		c := res.Coordinates
+		return f.Allows(c.GroupID, c.ArtifactID, c.Version)
+	case res.Scope == types.ScopeArtifact && n >= 2:
+		groupID, artifactID := strings.Join(segments[:n-1], "."), segments[n-1]
+		if !f.AllowsArtifact(groupID, artifactID) {
+			return false
+		}
+		if len(res.Files) > 0 {
+			// 只删除 artifactId 目录中的文件，例如 maven-metadata.xml
+			return true
+		}
+		entries, err := os.ReadDir(res.Path)
+		if err != nil {
+			return false
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !f.Allows(groupID, artifactID, entry.Name()) {
+				return false
+			}
+		}
+		return true
+	default:
+		return f.AllowsWholeGroup(strings.Join(segments, "."))
+	}
+}
+
+// segmentsOf 返回目录相对于仓库根目录的路径段
+func segmentsOf(root, dir string) ([]string, bool) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return nil, false
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	if slices.Contains(segments, "..") {
+		return nil, false
+	}
+	return segments, true
+}
+
+// match 判断值是否匹配通配符模式
+func match(pattern, value string) bool {
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// matchesAllUnder 判断 groupId 模式是否匹配 prefix 及所有以 "prefix." 开头的 groupId
+func matchesAllUnder(pattern, prefix string) bool {
+	if pattern == "*" {
+		return true
+	}
+	// 以 * 结尾的模式匹配 prefix 后，末尾的 * 也能匹配任意后缀
+	return strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, `\*`) && match(pattern, prefix)
+}
+
+// mayMatchUnder 判断 groupId 模式是否可能匹配 prefix 或某个以 "prefix." 开头的 groupId
+//
+// 只比较模式中通配符之前的字面前缀，结果偏保守。
+func mayMatchUnder(pattern, prefix string) bool {
+	literal := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		literal = pattern[:i]
+	} else {
+		return pattern == prefix || strings.HasPrefix(pattern, prefix+".")
+	}
+	return strings.HasPrefix(prefix, literal) || strings.HasPrefix(literal, prefix)
+}
@@ -0,0 +1,145 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Pattern
+		wantErr bool
+	}{
+		{"com.mycorp.*", Pattern{"com.mycorp.*", "*", "*"}, false},
+		{"org.apache.maven.plugins:*", Pattern{"org.apache.maven.plugins", "*", "*"}, false},
+		{" org.example:foo:1.* ", Pattern{"org.example", "foo", "1.*"}, false},
+		{"", Pattern{}, true},
+		{"a:b:c:d", Pattern{}, true},
+		{"org.example::1.0", Pattern{}, true},
+		{"org.[example", Pattern{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePattern(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePattern(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePattern(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f, err := New([]string{"com.mycorp.*:*", "org.example:lib"}, []string{"com.mycorp.legacy.*", "org.example:lib:1.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions := []struct {
+		coordinates [3]string
+		want        bool
+	}{
+		{[3]string{"com.mycorp.app", "app", "1.0"}, true},
+		{[3]string{"com.mycorp.legacy.db", "db", "1.0"}, false},
+		{[3]string{"com.mycorp", "root", "1.0"}, false},
+		{[3]string{"org.example", "lib", "2.0"}, true},
+		{[3]string{"org.example", "lib", "1.5"}, false},
+		{[3]string{"org.example", "other", "2.0"}, false},
+	}
+	for _, tt := range versions {
+		c := tt.coordinates
+		if got := f.Allows(c[0], c[1], c[2]); got != tt.want {
+			t.Errorf("Allows(%v) = %v, want %v", c, got, tt.want)
+		}
+	}
+
+	groups := []struct {
+		prefix    string
+		wantAny   bool // AllowsGroup
+		wantWhole bool // AllowsWholeGroup
+	}{
+		{"com", true, false},
+		{"com.mycorp", true, false},
+		{"com.mycorp.app", true, true},
+		// 直接位于 com/mycorp/legacy 下的构件的 groupId 为 com.mycorp.legacy，不匹配 com.mycorp.legacy.*
+		{"com.mycorp.legacy", true, false},
+		{"com.mycorp.legacy.db", false, false},
+		{"org", true, false},
+		{"org.example", true, false},
+		{"org.apache", false, false},
+		{"net", false, false},
+	}
+	for _, tt := range groups {
+		if got := f.AllowsGroup(tt.prefix); got != tt.wantAny {
+			t.Errorf("AllowsGroup(%s) = %v, want %v", tt.prefix, got, tt.wantAny)
+		}
+		if got := f.AllowsWholeGroup(tt.prefix); got != tt.wantWhole {
+			t.Errorf("AllowsWholeGroup(%s) = %v, want %v", tt.prefix, got, tt.wantWhole)
+		}
+	}
+
+	if !f.AllowsArtifact("org.example", "lib") {
+		t.Error("AllowsArtifact(org.example:lib) = false, want true: versions outside 1.* are included")
+	}
+	if f.AllowsArtifact("org.example", "other") {
+		t.Error("AllowsArtifact(org.example:other) = true, want false")
+	}
+}
+
+func TestNilFilter(t *testing.T) {
+	f, err := New(nil, nil)
+	if err != nil || f != nil {
+		t.Fatalf("New(nil, nil) = %v, %v, want nil filter", f, err)
+	}
+	if !f.Allows("any", "thing", "1.0") || !f.AllowsGroup("any") || !f.AllowsResult(types.Result{Path: "/x"}) {
+		t.Error("nil filter should allow everything")
+	}
+}
+
+func TestAllowsResult(t *testing.T) {
+	root := t.TempDir()
+	for _, version := range []string{"1.0", "2.0"} {
+		if err := os.MkdirAll(filepath.Join(root, "org", "example", "lib", version), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "org", "example", "app", "2.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := New(nil, []string{"org.example:lib:1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		res  types.Result
+		want bool
+	}{
+		{"allowed version", types.Result{Root: root, Path: filepath.Join(root, "org", "example", "lib", "2.0"), Scope: types.ScopeVersion}, true},
+		{"excluded version", types.Result{Root: root, Path: filepath.Join(root, "org", "example", "lib", "1.0"), Scope: types.ScopeVersion}, false},
+		{"artifact with an excluded version", types.Result{Root: root, Path: filepath.Join(root, "org", "example", "lib"), Scope: types.ScopeArtifact}, false},
+		{"artifact without excluded versions", types.Result{Root: root, Path: filepath.Join(root, "org", "example", "app"), Scope: types.ScopeArtifact}, true},
+		{"group containing an excluded version", types.Result{Root: root, Path: filepath.Join(root, "org", "example"), Scope: types.ScopeGroup}, false},
+		{"gradle SHA-1 directory of an excluded version", types.Result{Root: root, Path: filepath.Join(root, "org.example", "lib", "1.0", "0a1b"), Scope: types.ScopeFile}, false},
+		{"gradle SHA-1 directory of an allowed version", types.Result{Root: root, Path: filepath.Join(root, "org.example", "lib", "2.0", "0a1b"), Scope: types.ScopeFile}, true},
+		{"ivy files of an excluded revision", types.Result{Root: root, Path: filepath.Join(root, "org.example", "lib"), Scope: types.ScopeArtifact,
+			Files: []string{"ivydata-1.0.properties"}, Coordinates: types.Coordinates{GroupID: "org.example", ArtifactID: "lib", Version: "1.0"}}, false},
+		{"ivy files of an allowed revision", types.Result{Root: root, Path: filepath.Join(root, "org.example", "lib"), Scope: types.ScopeArtifact,
+			Files: []string{"ivydata-2.0.properties"}, Coordinates: types.Coordinates{GroupID: "org.example", ArtifactID: "lib", Version: "2.0"}}, true},
+		{"unknown root", types.Result{Path: filepath.Join(root, "org", "example", "app", "2.0"), Scope: types.ScopeVersion}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.AllowsResult(tt.res); got != tt.want {
+				t.Errorf("AllowsResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package pom
+
+import (
+	"encoding/xml"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+)
+
+// Metadata maven-metadata-*.xml 文件中 clean-mvn 关心的部分
+type Metadata struct {
+	GroupID    string     `xml:"groupId"`
+	ArtifactID string     `xml:"artifactId"`
+	Version    string     `xml:"version"`
+	Versioning Versioning `xml:"versioning"`
+}
+
+// Versioning 元数据中的版本信息
+type Versioning struct {
+	Latest           string            `xml:"latest"`
+	Release          string            `xml:"release"`
+	Versions         []string          `xml:"versions>version"`
+	Snapshot         Snapshot          `xml:"snapshot"`
+	SnapshotVersions []SnapshotVersion `xml:"snapshotVersions>snapshotVersion"`
+	LastUpdated      string            `xml:"lastUpdated"`
+}
+
+// Snapshot 快照版本目录中记录的最新构建
+type Snapshot struct {
+	Timestamp   string `xml:"timestamp"`
+	BuildNumber string `xml:"buildNumber"`
+	LocalCopy   bool   `xml:"localCopy"`
+}
+
+// SnapshotVersion 某个分类器和扩展名对应的当前快照构建
+type SnapshotVersion struct {
+	Classifier string `xml:"classifier"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
+
+// ParseMetadataFile 读取并解析 maven-metadata-*.xml 文件
+func ParseMetadataFile(path string) (*Metadata, error) {
+	file, err := atime.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	decoder.CharsetReader = charsetReader
+
+	var metadata Metadata
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,196 @@
+package pom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+)
+
+// Project POM 文件中 clean-mvn 关心的部分
+type Project struct {
+	XMLName              xml.Name
+	GroupID              string       `xml:"groupId"`
+	ArtifactID           string       `xml:"artifactId"`
+	Version              string       `xml:"version"`
+	Packaging            string       `xml:"packaging"`
+	Parent               *Parent      `xml:"parent"`
+	Modules              []string     `xml:"modules>module"`
+	Properties           Properties   `xml:"properties"`
+	Dependencies         []Dependency `xml:"dependencies>dependency"`
+	DependencyManagement []Dependency `xml:"dependencyManagement>dependencies>dependency"`
+	Build                Build        `xml:"build"`
+}
+
+// Parent POM 中声明的父项目
+type Parent struct {
+	GroupID      string `xml:"groupId"`
+	ArtifactID   string `xml:"artifactId"`
+	Version      string `xml:"version"`
+	RelativePath string `xml:"relativePath"`
+}
+
+// Dependency POM 中声明的依赖
+type Dependency struct {
+	GroupID    string      `xml:"groupId"`
+	ArtifactID string      `xml:"artifactId"`
+	Version    string      `xml:"version"`
+	Type       string      `xml:"type"`
+	Classifier string      `xml:"classifier"`
+	Scope      string      `xml:"scope"`
+	Optional   string      `xml:"optional"`
+	Exclusions []Exclusion `xml:"exclusions>exclusion"`
+}
+
+// Exclusion 依赖中排除的传递依赖，groupId 和 artifactId 可以为 *
+type Exclusion struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+// Build POM 中与插件相关的构建配置
+type Build struct {
+	Plugins          []Plugin `xml:"plugins>plugin"`
+	PluginManagement []Plugin `xml:"pluginManagement>plugins>plugin"`
+	Extensions       []Plugin `xml:"extensions>extension"`
+}
+
+// Plugin 构建插件或构建扩展
+type Plugin struct {
+	GroupID      string       `xml:"groupId"`
+	ArtifactID   string       `xml:"artifactId"`
+	Version      string       `xml:"version"`
+	Dependencies []Dependency `xml:"dependencies>dependency"`
+}
+
+// Properties POM 中 <properties> 元素下的属性
+type Properties map[string]string
+
+// UnmarshalXML 将 <properties> 的每个子元素解析为一个属性
+func (p *Properties) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	if *p == nil {
+		*p = make(Properties)
+	}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err := decoder.DecodeElement(&value, &element); err != nil {
+				return err
+			}
+			(*p)[element.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// EffectiveGroupID 返回项目的 groupId，未声明时继承父项目的 groupId
+func (p *Project) EffectiveGroupID() string {
+	if p.GroupID == "" && p.Parent != nil {
+		return strings.TrimSpace(p.Parent.GroupID)
+	}
+	return strings.TrimSpace(p.GroupID)
+}
+
+// EffectiveVersion 返回项目的版本，未声明时继承父项目的版本
+func (p *Project) EffectiveVersion() string {
+	if p.Version == "" && p.Parent != nil {
+		return strings.TrimSpace(p.Parent.Version)
+	}
+	return strings.TrimSpace(p.Version)
+}
+
+// EffectivePackaging 返回项目的打包类型，未声明时为 jar
+func (p *Project) EffectivePackaging() string {
+	if packaging := strings.TrimSpace(p.Packaging); packaging != "" {
+		return packaging
+	}
+	return "jar"
+}
+
+// Parse 解析 POM 内容，根元素不是 <project> 时返回错误
+func Parse(r io.Reader) (*Project, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charsetReader
+
+	var project Project
+	if err := decoder.Decode(&project); err != nil {
+		return nil, err
+	}
+	if project.XMLName.Local != "project" {
+		return nil, fmt.Errorf("root element is <%s>, expected <project>", project.XMLName.Local)
+	}
+	return &project, nil
+}
+
+// ParseFile 读取并解析 POM 文件
+func ParseFile(path string) (*Project, error) {
+	file, err := atime.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// charsetReader 支持老旧 POM 中常见的 ISO-8859-1 等单字节编码，其他编码按 UTF-8 读取
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "iso8859-1", "latin1", "windows-1252", "cp1252":
+		return &latin1Reader{r: input}, nil
+	}
+	return input, nil
+}
+
+// latin1Reader 将 ISO-8859-1 字节流转换为 UTF-8
+type latin1Reader struct {
+	r       io.Reader
+	pending []byte
+}
+
+func (lr *latin1Reader) Read(p []byte) (int, error) {
+	if len(lr.pending) == 0 {
+		buf := make([]byte, len(p)/2+1)
+		n, err := lr.r.Read(buf)
+		for _, b := range buf[:n] {
+			lr.pending = utf8.AppendRune(lr.pending, rune(b))
+		}
+		if n == 0 {
+			return 0, err
+		}
+	}
+	n := copy(p, lr.pending)
+	lr.pending = lr.pending[n:]
+	return n, nil
+}
+
+// packagingExtensions 常见打包类型对应的主构件扩展名，pom 打包没有主构件
+var packagingExtensions = map[string]string{
+	"jar":             "jar",
+	"bundle":          "jar",
+	"maven-plugin":    "jar",
+	"maven-archetype": "jar",
+	"ejb":             "jar",
+	"test-jar":        "jar",
+	"java-source":     "jar",
+	"javadoc":         "jar",
+	"war":             "war",
+	"ear":             "ear",
+	"rar":             "rar",
+	"aar":             "aar",
+	"pom":             "",
+}
+
+// ArtifactExtension 返回打包类型对应的主构件扩展名，ok 为 false 表示未知的打包类型
+func ArtifactExtension(packaging string) (ext string, ok bool) {
+	ext, ok = packagingExtensions[packaging]
+	return ext, ok
+}
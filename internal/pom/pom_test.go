@@ -0,0 +1,127 @@
+package pom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantErr       bool
+		wantGroupID   string
+		wantVersion   string
+		wantPackaging string
+	}{
+		{
+			name:          "full coordinates",
+			input:         `<project><groupId>org.example</groupId><artifactId>foo</artifactId><version>1.0</version><packaging>war</packaging></project>`,
+			wantGroupID:   "org.example",
+			wantVersion:   "1.0",
+			wantPackaging: "war",
+		},
+		{
+			name: "inherited from parent",
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>2.0</version></parent>
+  <artifactId>foo</artifactId>
+</project>`,
+			wantGroupID:   "org.example",
+			wantVersion:   "2.0",
+			wantPackaging: "jar",
+		},
+		{
+			name:          "latin1 encoding",
+			input:         "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><project><groupId>org.example</groupId><version>1.0</version><name>caf\xe9</name></project>",
+			wantGroupID:   "org.example",
+			wantVersion:   "1.0",
+			wantPackaging: "jar",
+		},
+		{
+			name:    "truncated",
+			input:   `<project><groupId>org.example</groupId><artifactId>fo`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong root element",
+			input:   `<html><body>Not Found</body></html>`,
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			input:   ``,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := Parse(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := project.EffectiveGroupID(); got != tt.wantGroupID {
+				t.Errorf("EffectiveGroupID() = %q, want %q", got, tt.wantGroupID)
+			}
+			if got := project.EffectiveVersion(); got != tt.wantVersion {
+				t.Errorf("EffectiveVersion() = %q, want %q", got, tt.wantVersion)
+			}
+			if got := project.EffectivePackaging(); got != tt.wantPackaging {
+				t.Errorf("EffectivePackaging() = %q, want %q", got, tt.wantPackaging)
+			}
+		})
+	}
+}
+
+func TestParseModel(t *testing.T) {
+	input := `<project>
+  <artifactId>foo</artifactId>
+  <modules><module>core</module><module>web</module></modules>
+  <properties><lib.version>2.0</lib.version><empty/></properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.lib</groupId><artifactId>lib</artifactId><version>${lib.version}</version><scope>test</scope>
+      <exclusions><exclusion><groupId>org.ex</groupId><artifactId>*</artifactId></exclusion></exclusions>
+    </dependency>
+  </dependencies>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.bom</groupId><artifactId>bom</artifactId><version>1.0</version><type>pom</type><scope>import</scope></dependency>
+  </dependencies></dependencyManagement>
+  <build>
+    <plugins><plugin><artifactId>maven-compiler-plugin</artifactId><version>3.11.0</version></plugin></plugins>
+    <extensions><extension><groupId>org.ext</groupId><artifactId>wagon</artifactId><version>1.0</version></extension></extensions>
+  </build>
+</project>`
+
+	project, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(project.Modules) != 2 || project.Modules[1] != "web" {
+		t.Errorf("Modules = %v, want [core web]", project.Modules)
+	}
+	if project.Properties["lib.version"] != "2.0" {
+		t.Errorf("Properties = %v, want lib.version=2.0", project.Properties)
+	}
+	if value, ok := project.Properties["empty"]; !ok || value != "" {
+		t.Errorf("Properties[empty] = %q, %v, want empty property", value, ok)
+	}
+	if len(project.Dependencies) != 1 || project.Dependencies[0].Scope != "test" ||
+		len(project.Dependencies[0].Exclusions) != 1 || project.Dependencies[0].Exclusions[0].ArtifactID != "*" {
+		t.Errorf("Dependencies = %+v", project.Dependencies)
+	}
+	if len(project.DependencyManagement) != 1 || project.DependencyManagement[0].Scope != "import" {
+		t.Errorf("DependencyManagement = %+v", project.DependencyManagement)
+	}
+	if len(project.Build.Plugins) != 1 || project.Build.Plugins[0].Version != "3.11.0" {
+		t.Errorf("Build.Plugins = %+v", project.Build.Plugins)
+	}
+	if len(project.Build.Extensions) != 1 || project.Build.Extensions[0].ArtifactID != "wagon" {
+		t.Errorf("Build.Extensions = %+v", project.Build.Extensions)
+	}
+}
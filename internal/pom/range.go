@@ -0,0 +1,107 @@
+package pom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VersionRange Maven 的版本范围，由一个或多个以逗号分隔的区间组成，如 [1.0,2.0)、(,1.0],[1.2,)、[1.5]
+type VersionRange struct {
+	spec         string
+	restrictions []restriction
+}
+
+// restriction 版本范围中的一个区间，边界为 nil 表示不限
+type restriction struct {
+	lower, upper                   *ComparableVersion
+	lowerInclusive, upperInclusive bool
+}
+
+// IsVersionRange 判断版本是否为 [1.0,2.0) 形式的版本范围
+func IsVersionRange(version string) bool {
+	return strings.HasPrefix(version, "[") || strings.HasPrefix(version, "(")
+}
+
+// ParseVersionRange 解析版本范围，不是合法的范围时返回错误
+func ParseVersionRange(spec string) (*VersionRange, error) {
+	vr := &VersionRange{spec: spec}
+	rest := strings.TrimSpace(spec)
+	for rest != "" {
+		if rest[0] != '[' && rest[0] != '(' {
+			return nil, fmt.Errorf("invalid version range %q", spec)
+		}
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("unbounded version range %q", spec)
+		}
+		r, err := parseRestriction(rest[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range %q: %w", spec, err)
+		}
+		vr.restrictions = append(vr.restrictions, r)
+
+		rest = strings.TrimSpace(rest[end+1:])
+		if next, ok := strings.CutPrefix(rest, ","); ok {
+			rest = strings.TrimSpace(next)
+		} else if rest != "" {
+			return nil, fmt.Errorf("invalid version range %q", spec)
+		}
+	}
+	if len(vr.restrictions) == 0 {
+		return nil, fmt.Errorf("empty version range %q", spec)
+	}
+	return vr, nil
+}
+
+// parseRestriction 解析 "[下界,上界)" 形式的单个区间，"[1.0]" 表示只匹配该版本
+func parseRestriction(spec string) (restriction, error) {
+	r := restriction{lowerInclusive: spec[0] == '[', upperInclusive: spec[len(spec)-1] == ']'}
+	inner := strings.TrimSpace(spec[1 : len(spec)-1])
+
+	lower, upper, ok := strings.Cut(inner, ",")
+	if !ok {
+		if !r.lowerInclusive || !r.upperInclusive || inner == "" {
+			return r, fmt.Errorf("single version %s must be written as [version]", spec)
+		}
+		r.lower = ParseVersion(inner)
+		r.upper = r.lower
+		return r, nil
+	}
+	if strings.Contains(upper, ",") {
+		return r, fmt.Errorf("%s has more than two bounds", spec)
+	}
+	if lower = strings.TrimSpace(lower); lower != "" {
+		r.lower = ParseVersion(lower)
+	}
+	if upper = strings.TrimSpace(upper); upper != "" {
+		r.upper = ParseVersion(upper)
+	}
+	if r.lower != nil && r.upper != nil && r.lower.Compare(r.upper) > 0 {
+		return r, fmt.Errorf("%s has a lower bound above its upper bound", spec)
+	}
+	return r, nil
+}
+
+// String 返回原始的范围表示
+func (vr *VersionRange) String() string {
+	return vr.spec
+}
+
+// Contains 判断版本是否落在任意一个区间内
+func (vr *VersionRange) Contains(version string) bool {
+	v := ParseVersion(version)
+	for _, r := range vr.restrictions {
+		if r.lower != nil {
+			if c := v.Compare(r.lower); c < 0 || c == 0 && !r.lowerInclusive {
+				continue
+			}
+		}
+		if r.upper != nil {
+			if c := v.Compare(r.upper); c > 0 || c == 0 && !r.upperInclusive {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package pom
+
+import "testing"
+
+func TestVersionRangeContains(t *testing.T) {
+	tests := []struct {
+		spec string
+		in   []string
+		out  []string
+	}{
+		{"[1.0,2.0)", []string{"1.0", "1.5", "1.9.9"}, []string{"0.9", "2.0", "2.1"}},
+		{"[2.0,)", []string{"2.0", "2.1", "10"}, []string{"1.0", "2.0-SNAPSHOT"}},
+		{"(,1.0]", []string{"0.1", "1.0"}, []string{"1.0.1", "1.1"}},
+		{"(1.0,2.0]", []string{"1.0.1", "2.0"}, []string{"1.0", "2.1"}},
+		{"[1.5]", []string{"1.5", "1.5.0"}, []string{"1.4", "1.6"}},
+		{"(,1.0],[1.2,)", []string{"0.9", "1.0", "1.2", "3"}, []string{"1.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			vr, err := ParseVersionRange(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseVersionRange(%q) error = %v", tt.spec, err)
+			}
+			for _, v := range tt.in {
+				if !vr.Contains(v) {
+					t.Errorf("%s.Contains(%q) = false, want true", tt.spec, v)
+				}
+			}
+			for _, v := range tt.out {
+				if vr.Contains(v) {
+					t.Errorf("%s.Contains(%q) = true, want false", tt.spec, v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVersionRangeInvalid(t *testing.T) {
+	for _, spec := range []string{"", "1.0", "[1.0", "(1.0)", "[2.0,1.0]", "[1.0,2.0,3.0]", "[1.0,2.0)x"} {
+		if _, err := ParseVersionRange(spec); err == nil {
+			t.Errorf("ParseVersionRange(%q) error = nil, want error", spec)
+		}
+	}
+}
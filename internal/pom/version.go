@@ -0,0 +1,271 @@
+package pom
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ComparableVersion 按 Maven ComparableVersion 的语义比较版本号
+//
+// 版本号按 '.'、'-' 以及数字与字母的交界拆分为数字项和字符串项，'-' 开始一个子列表。
+// 已知限定符的顺序为 alpha < beta < milestone < rc = cr < snapshot < "" = ga = final = release < sp，
+// 未知限定符排在已知限定符之后并按字典序比较，数字项总是大于字符串项。
+type ComparableVersion struct {
+	value string
+	items *versionList
+}
+
+// ParseVersion 解析版本号
+func ParseVersion(version string) *ComparableVersion {
+	return &ComparableVersion{value: version, items: parseVersionItems(version)}
+}
+
+// String 返回原始版本号
+func (cv *ComparableVersion) String() string {
+	return cv.value
+}
+
+// Compare 比较两个版本，小于、等于、大于时分别返回 -1、0、1
+func (cv *ComparableVersion) Compare(other *ComparableVersion) int {
+	return cv.items.compare(other.items)
+}
+
+// CompareVersions 按 Maven 语义比较两个版本号字符串
+func CompareVersions(a, b string) int {
+	return ParseVersion(a).Compare(ParseVersion(b))
+}
+
+// qualifiers 已知限定符，按从旧到新排列
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// releaseQualifierIndex 正式版（空限定符）在 qualifiers 中的位置
+const releaseQualifierIndex = "5"
+
+// qualifierAliases 限定符别名
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// versionItem 版本号中的一项，compare 的参数为 nil 时表示与空项比较
+type versionItem interface {
+	compare(other versionItem) int
+	isNull() bool
+}
+
+// intItem 数字项，保存去掉前导零的十进制字符串以支持任意长度
+type intItem string
+
+func newIntItem(digits string) intItem {
+	return intItem(strings.TrimLeft(digits, "0"))
+}
+
+func (ii intItem) isNull() bool {
+	return ii == ""
+}
+
+func (ii intItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if ii.isNull() {
+			return 0
+		}
+		return 1
+	case intItem:
+		if len(ii) != len(o) {
+			return sign(len(ii) - len(o))
+		}
+		return strings.Compare(string(ii), string(o))
+	case stringItem:
+		return 1
+	default:
+		return 1
+	}
+}
+
+// stringItem 字符串项，保存别名替换后的限定符
+type stringItem string
+
+func newStringItem(value string, followedByDigit bool) stringItem {
+	if followedByDigit && len(value) == 1 {
+		// 1.0-a1 等价于 1.0-alpha-1
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[value]; ok {
+		value = alias
+	}
+	return stringItem(value)
+}
+
+// comparable 返回用于排序的限定符键，未知限定符排在所有已知限定符之后
+func (si stringItem) comparable() string {
+	for i, qualifier := range qualifiers {
+		if string(si) == qualifier {
+			return strconv.Itoa(i)
+		}
+	}
+	return strconv.Itoa(len(qualifiers)) + "-" + string(si)
+}
+
+func (si stringItem) isNull() bool {
+	return si.comparable() == releaseQualifierIndex
+}
+
+func (si stringItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		return strings.Compare(si.comparable(), releaseQualifierIndex)
+	case intItem:
+		return -1
+	case stringItem:
+		return strings.Compare(si.comparable(), o.comparable())
+	default:
+		return -1
+	}
+}
+
+// versionList 由 '-' 或数字字母交界开始的子列表
+type versionList struct {
+	items []versionItem
+}
+
+func (vl *versionList) isNull() bool {
+	return len(vl.items) == 0
+}
+
+// normalize 去掉末尾的空项，例如 1.0.0 与 1 等价
+func (vl *versionList) normalize() {
+	for i := len(vl.items) - 1; i >= 0; i-- {
+		item := vl.items[i]
+		if item.isNull() {
+			vl.items = append(vl.items[:i], vl.items[i+1:]...)
+		} else if _, ok := item.(*versionList); !ok {
+			break
+		}
+	}
+}
+
+func (vl *versionList) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(vl.items) == 0 {
+			return 0
+		}
+		return vl.items[0].compare(nil)
+	case intItem:
+		return -1
+	case stringItem:
+		return 1
+	case *versionList:
+		for i := 0; i < len(vl.items) || i < len(o.items); i++ {
+			var left, right versionItem
+			if i < len(vl.items) {
+				left = vl.items[i]
+			}
+			if i < len(o.items) {
+				right = o.items[i]
+			}
+
+			var result int
+			switch {
+			case left == nil && right == nil:
+				result = 0
+			case left == nil:
+				result = -right.compare(nil)
+			default:
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// parseVersionItems 将版本号拆分为嵌套的版本项列表
+func parseVersionItems(version string) *versionList {
+	version = strings.ToLower(version)
+
+	root := &versionList{}
+	list := root
+	stack := []*versionList{root}
+	push := func() {
+		child := &versionList{}
+		list.items = append(list.items, child)
+		list = child
+		stack = append(stack, child)
+	}
+	parseItem := func(isDigit bool, value string, followedByDigit bool) versionItem {
+		if isDigit {
+			return newIntItem(value)
+		}
+		return newStringItem(value, followedByDigit)
+	}
+
+	isDigit := false
+	start := 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.':
+			if i == start {
+				list.items = append(list.items, intItem(""))
+			} else {
+				list.items = append(list.items, parseItem(isDigit, version[start:i], false))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.items = append(list.items, intItem(""))
+			} else {
+				list.items = append(list.items, parseItem(isDigit, version[start:i], false))
+			}
+			start = i + 1
+			push()
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, newStringItem(version[start:i], true))
+				start = i
+				push()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, parseItem(true, version[start:i], false))
+				start = i
+				push()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.items = append(list.items, parseItem(isDigit, version[start:], false))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return root
+}
+
+// sign 返回整数的符号
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
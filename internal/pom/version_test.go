@@ -0,0 +1,76 @@
+package pom
+
+import "testing"
+
+// 以下序列取自 Maven ComparableVersionTest，每个版本都严格小于其后的版本
+var orderedQualifierVersions = []string{
+	"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+	"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+	"1-1", "1-2", "1-123",
+}
+
+var orderedNumberVersions = []string{
+	"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1",
+	"2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+}
+
+func TestCompareVersionsOrdering(t *testing.T) {
+	for _, versions := range [][]string{orderedQualifierVersions, orderedNumberVersions} {
+		for i := range versions {
+			for j := i + 1; j < len(versions); j++ {
+				if got := CompareVersions(versions[i], versions[j]); got >= 0 {
+					t.Errorf("CompareVersions(%q, %q) = %d, want < 0", versions[i], versions[j], got)
+				}
+				if got := CompareVersions(versions[j], versions[i]); got <= 0 {
+					t.Errorf("CompareVersions(%q, %q) = %d, want > 0", versions[j], versions[i], got)
+				}
+			}
+		}
+	}
+}
+
+func TestCompareVersionsEqual(t *testing.T) {
+	tests := [][]string{
+		{"1", "1.0", "1.0.0", "1-ga", "1.0-final", "1-release", "1.0.0-GA"},
+		{"1-alpha1", "1-a1", "1-ALPHA-1"},
+		{"1-beta1", "1-b1"},
+		{"1-milestone1", "1-m1"},
+		{"1-rc1", "1-cr1", "1-RC1"},
+		{"1.0.0-x", "1-x"},
+		{"12345678901234567890", "0012345678901234567890"},
+	}
+
+	for _, equal := range tests {
+		for _, a := range equal {
+			for _, b := range equal {
+				if got := CompareVersions(a, b); got != 0 {
+					t.Errorf("CompareVersions(%q, %q) = %d, want 0", a, b, got)
+				}
+			}
+		}
+	}
+}
+
+func TestCompareVersionsCommon(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.9", "1.10", -1},
+		{"1.10", "1.9.9", 1},
+		{"2.0-RC1", "2.0", -1},
+		{"2.0-alpha", "2.0-beta", -1},
+		{"2.0-SNAPSHOT", "2.0", -1},
+		{"2.0-RC1", "2.0-SNAPSHOT", -1},
+		{"1.0", "1.0-sp1", -1},
+		{"99999999999999999999", "100000000000000000000", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			if got := CompareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package repository 识别本地仓库的目录布局
+//
+// Maven 3.9 设置 aether.enhancedLocalRepository.split=true 后，本地仓库被分为 installed/ 和 cached/ 两部分，
+// 还可以按远程仓库 id（splitRemoteRepository）以及正式版本和快照版本（splitLocal、splitRemote）进一步分离。
+// Gradle 的模块缓存（caches/modules-2/files-2.1）和 Ivy 缓存（~/.ivy2/cache）作为其他仓库类型识别。
+package repository
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+const (
+	installedDir = "installed"
+	cachedDir    = "cached"
+	releasesDir  = "releases"
+	snapshotsDir = "snapshots"
+	// remoteRepositoriesFile 记录版本目录中每个文件来自哪个远程仓库
+	remoteRepositoriesFile = "_remote.repositories"
+	// gradleFilesDir Gradle 模块缓存中按 "<group>/<module>/<version>/<sha1>/<文件>" 存放文件的目录
+	gradleFilesDir = "files-2.1"
+	// ivyCacheDir Ivy 用户目录（~/.ivy2）中的缓存目录
+	ivyCacheDir = "cache"
+	// ivyDataPrefix Ivy 在模块目录中为每个版本记录来源和解析状态的 ivydata-<revision>.properties
+	ivyDataPrefix = "ivydata-"
+)
+
+// gradleCacheDirs Gradle 模块缓存相对于 modules-2、caches 和 Gradle 用户目录（~/.gradle）的位置
+var gradleCacheDirs = []string{
+	gradleFilesDir,
+	filepath.Join("modules-2", gradleFilesDir),
+	filepath.Join("caches", "modules-2", gradleFilesDir),
+}
+
+// Trees 返回本地仓库中的目录树
+//
+// 未分离的仓库只有根目录一棵树；分离布局按 installed、cached 的顺序返回每棵子树；
+// Gradle 用户目录、caches 或 modules-2 目录返回其中的 files-2.1 目录；Ivy 用户目录返回其中的缓存目录。
+func Trees(root string) []types.Tree {
+	if cache, ok := GradleCache(root); ok {
+		return []types.Tree{{Path: cache, Type: types.RepositoryGradle}}
+	}
+	if cache, ok := IvyCache(root); ok {
+		return []types.Tree{{Path: cache, Type: types.RepositoryIvy}}
+	}
+	if !IsSplit(root) {
+		return []types.Tree{{Path: root}}
+	}
+
+	var trees []types.Tree
+	if dir := filepath.Join(root, installedDir); isDir(dir) {
+		trees = append(trees, expand(types.Tree{Path: dir, Kind: types.TreeInstalled}, false)...)
+	}
+	if dir := filepath.Join(root, cachedDir); isDir(dir) {
+		trees = append(trees, expand(types.Tree{Path: dir, Kind: types.TreeCached}, true)...)
+	}
+	return trees
+}
+
+// IsSplit 判断本地仓库是否使用分离布局：根目录下的子目录只有 installed 和 cached
+//
+// Maven 3.9 的文件锁在根目录下创建的 .locks 等隐藏目录不影响判断。
+func IsSplit(root string) bool {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return false
+	}
+	found := false
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if entry.Name() != installedDir && entry.Name() != cachedDir {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// GradleCache 判断路径是否为 Gradle 模块缓存或其上级目录，返回 files-2.1 目录
+func GradleCache(root string) (string, bool) {
+	if filepath.Base(filepath.Clean(root)) == gradleFilesDir {
+		return root, isDir(root)
+	}
+	for _, dir := range gradleCacheDirs {
+		if cache := filepath.Join(root, dir); isDir(cache) {
+			return cache, true
+		}
+	}
+	return "", false
+}
+
+// IvyCache 判断路径是否为 Ivy 缓存或 Ivy 用户目录（~/.ivy2），返回缓存目录
+//
+// 缓存目录中的模块目录 "<organisation>/<module>" 包含 ivydata-<revision>.properties。
+func IvyCache(root string) (string, bool) {
+	for _, cache := range []string{root, filepath.Join(root, ivyCacheDir)} {
+		if isIvyCache(cache) {
+			return cache, true
+		}
+	}
+	return "", false
+}
+
+// isIvyCache 判断目录中是否有包含 ivydata-<revision>.properties 的模块目录
+func isIvyCache(dir string) bool {
+	for _, organisation := range subdirs(dir) {
+		for _, module := range subdirs(filepath.Join(dir, organisation)) {
+			entries, err := os.ReadDir(filepath.Join(dir, organisation, module))
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() && strings.HasPrefix(entry.Name(), ivyDataPrefix) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// expand 展开按正式版本、快照版本或远程仓库 id 分离的子目录，直到到达按坐标组织的目录树
+func expand(tree types.Tree, allowRepositoryID bool) []types.Tree {
+	children := subdirs(tree.Path)
+	if len(children) == 0 {
+		return []types.Tree{tree}
+	}
+
+	if allOf(children, releasesDir, snapshotsDir) {
+		var trees []types.Tree
+		for _, child := range children {
+			sub := tree
+			sub.Path = filepath.Join(tree.Path, child)
+			// splitRemoteRepositoryLast 时远程仓库 id 位于 releases/snapshots 之下
+			trees = append(trees, expand(sub, allowRepositoryID)...)
+		}
+		return trees
+	}
+
+	if allowRepositoryID && tree.RepositoryID == "" && isRepositoryID(tree.Path, children) {
+		var trees []types.Tree
+		for _, child := range children {
+			sub := tree
+			sub.Path = filepath.Join(tree.Path, child)
+			sub.RepositoryID = child
+			trees = append(trees, expand(sub, false)...)
+		}
+		return trees
+	}
+
+	return []types.Tree{tree}
+}
+
+// isRepositoryID 判断子目录名是否为远程仓库 id：子目录中的 _remote.repositories 记录了来自同名远程仓库的文件
+func isRepositoryID(dir string, children []string) bool {
+	for _, child := range children {
+		ids, ok := firstRemoteRepositories(filepath.Join(dir, child))
+		if !ok {
+			continue
+		}
+		return ids[child]
+	}
+	return false
+}
+
+// firstRemoteRepositories 读取目录下找到的第一个 _remote.repositories，返回其中记录的远程仓库 id
+func firstRemoteRepositories(dir string) (map[string]bool, bool) {
+	var found string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && d.Name() == remoteRepositoriesFile {
+			found = path
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if found == "" {
+		return nil, false
+	}
+	ids, err := readRemoteRepositories(found)
+	if err != nil || len(ids) == 0 {
+		return nil, false
+	}
+	return ids, true
+}
+
+// readRemoteRepositories 解析 _remote.repositories，返回其中记录的远程仓库 id
+//
+// 每行形如 "foo-1.0.jar>central="，本地安装的文件没有仓库 id，记录为 "foo-1.0.jar>="。
+func readRemoteRepositories(path string) (map[string]bool, error) {
+	file, err := atime.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ids := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		_, rest, ok := strings.Cut(line, ">")
+		if !ok {
+			continue
+		}
+		if id, _, _ := strings.Cut(rest, "="); id != "" {
+			ids[id] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// subdirs 返回目录下按名称排序的子目录名
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// allOf 判断每个名称都在允许的集合中
+func allOf(names []string, allowed ...string) bool {
+	for _, name := range names {
+		if !slices.Contains(allowed, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDir 判断路径是否为目录
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
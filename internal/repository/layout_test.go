@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrees(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []types.Tree // Path 相对于仓库根目录
+	}{
+		{
+			name:  "flat repository",
+			files: map[string]string{"org/example/foo/1.0/foo-1.0.jar": ""},
+			want:  []types.Tree{{Path: "."}},
+		},
+		{
+			name: "flat repository with a cached group",
+			files: map[string]string{
+				"cached/foo/1.0/foo-1.0.jar":      "",
+				"org/example/foo/1.0/foo-1.0.jar": "",
+			},
+			want: []types.Tree{{Path: "."}},
+		},
+		{
+			name: "installed and cached",
+			files: map[string]string{
+				"installed/com/acme/app/1.0/app-1.0.jar":               "",
+				"cached/org/example/foo/1.0/foo-1.0.jar":               "",
+				"cached/org/example/foo/1.0/" + remoteRepositoriesFile: "foo-1.0.jar>central=\n",
+			},
+			want: []types.Tree{
+				{Path: "installed", Kind: types.TreeInstalled},
+				{Path: "cached", Kind: types.TreeCached},
+			},
+		},
+		{
+			name: "installed and cached with a lock directory",
+			files: map[string]string{
+				"installed/com/acme/app/1.0/app-1.0.jar":                  "",
+				"cached/org/example/foo/1.0/foo-1.0.jar":                  "",
+				".locks/org.example~foo~1.0/org.example~foo~1.0.jar.lock": "",
+			},
+			want: []types.Tree{
+				{Path: "installed", Kind: types.TreeInstalled},
+				{Path: "cached", Kind: types.TreeCached},
+			},
+		},
+		{
+			name: "split by remote repository",
+			files: map[string]string{
+				"cached/central/org/example/foo/1.0/" + remoteRepositoriesFile: "#NOTE\nfoo-1.0.jar>central=\n",
+				"cached/corp/com/corp/lib/2.0/" + remoteRepositoriesFile:       "lib-2.0.jar>corp=\n",
+			},
+			want: []types.Tree{
+				{Path: "cached/central", Kind: types.TreeCached, RepositoryID: "central"},
+				{Path: "cached/corp", Kind: types.TreeCached, RepositoryID: "corp"},
+			},
+		},
+		{
+			name:  "gradle user home",
+			files: map[string]string{"caches/modules-2/files-2.1/org.example/foo/1.0/0a1b2c/foo-1.0.jar": ""},
+			want:  []types.Tree{{Path: "caches/modules-2/files-2.1", Type: types.RepositoryGradle}},
+		},
+		{
+			name:  "gradle modules-2",
+			files: map[string]string{"files-2.1/org.example/foo/1.0/0a1b2c/foo-1.0.jar": ""},
+			want:  []types.Tree{{Path: "files-2.1", Type: types.RepositoryGradle}},
+		},
+		{
+			name: "ivy home",
+			files: map[string]string{
+				"cache/org.example/foo/ivydata-1.0.properties": "",
+				"cache/org.example/foo/jars/foo-1.0.jar":       "",
+			},
+			want: []types.Tree{{Path: "cache", Type: types.RepositoryIvy}},
+		},
+		{
+			name: "split by release and snapshot",
+			files: map[string]string{
+				"installed/releases/com/acme/app/1.0/app-1.0.jar":                                 "",
+				"installed/snapshots/com/acme/app/2.0-SNAPSHOT/app-2.0-SNAPSHOT.jar":              "",
+				"cached/releases/central/org/example/foo/1.0/" + remoteRepositoriesFile:           "foo-1.0.jar>central=\n",
+				"cached/snapshots/central/org/example/foo/2.0-SNAPSHOT/" + remoteRepositoriesFile: "foo-2.0-SNAPSHOT.jar>central=\n",
+			},
+			want: []types.Tree{
+				{Path: "installed/releases", Kind: types.TreeInstalled},
+				{Path: "installed/snapshots", Kind: types.TreeInstalled},
+				{Path: "cached/releases/central", Kind: types.TreeCached, RepositoryID: "central"},
+				{Path: "cached/snapshots/central", Kind: types.TreeCached, RepositoryID: "central"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(root, name), content)
+			}
+
+			var want []types.Tree
+			for _, tree := range tt.want {
+				tree.Path = filepath.Join(root, tree.Path)
+				want = append(want, tree)
+			}
+			if got := Trees(root); !slices.Equal(got, want) {
+				t.Errorf("Trees() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
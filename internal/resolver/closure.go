@@ -0,0 +1,279 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+)
+
+// Node 闭包中需要从本地仓库获取的构件
+type Node struct {
+	Artifact Artifact
+	Path     []string // 从项目到该构件的依赖路径，不含构件本身
+}
+
+// Problem 解析闭包时无法从本地仓库读取的 POM 或无法确定版本的依赖
+type Problem struct {
+	Artifact Artifact
+	Path     []string
+	Reason   string
+	NotFound bool // 本地仓库中没有该构件的任何版本
+}
+
+// Closure 项目的依赖闭包
+//
+// 包含项目自身、父 POM、导入的 BOM、依赖及其传递依赖、构建插件和扩展及其依赖。
+// 不做版本仲裁，闭包中出现过的每个版本都会保留；未声明版本的插件和版本范围保留本地所有版本。
+type Closure struct {
+	Projects []Artifact // 反应堆中的项目
+	Nodes    []Node
+	Problems []Problem
+
+	versions    map[string]bool // "groupId:artifactId:version"
+	anyVersions map[string]bool // "groupId:artifactId"，保留所有版本
+	nodes       map[string]bool // 已加入 Nodes 的构件，见 Artifact.id
+	problems    map[string]bool
+}
+
+// Contains 判断某个版本目录是否属于闭包
+func (c *Closure) Contains(groupID, artifactID, version string) bool {
+	return c.versions[groupID+":"+artifactID+":"+version] || c.anyVersions[groupID+":"+artifactID]
+}
+
+// queued 等待处理的依赖
+type queued struct {
+	dep        pom.Dependency
+	path       []string
+	exclusions []pom.Exclusion
+	root       *model // 项目模型，其依赖管理作用于所有传递依赖
+	transitive bool
+	anyVersion bool // 版本未确定，保留本地所有版本
+}
+
+// Resolve 读取项目目录（或 POM 文件）及其模块，计算整个反应堆的依赖闭包
+func (r *Resolver) Resolve(projects []string) (*Closure, error) {
+	closure := &Closure{
+		versions:    make(map[string]bool),
+		anyVersions: make(map[string]bool),
+		nodes:       make(map[string]bool),
+		problems:    make(map[string]bool),
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, project := range projects {
+		reactor, err := r.loadReactor(project, seen)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", project, err)
+		}
+		paths = append(paths, reactor...)
+	}
+
+	var queue []queued
+	for _, path := range paths {
+		m, err := r.projectModel(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		closure.Projects = append(closure.Projects, m.project)
+		closure.versions[m.project.Key()] = true
+
+		path := []string{m.project.Key()}
+		closure.addModelPOMs(m, path)
+		for _, dep := range m.dependencies {
+			if dep.Scope == "system" {
+				continue
+			}
+			queue = append(queue, queued{dep: dep, path: path, exclusions: dep.Exclusions, root: m})
+		}
+		for _, plugin := range append(m.plugins, m.extensions...) {
+			pluginDep := pom.Dependency{GroupID: plugin.GroupID, ArtifactID: plugin.ArtifactID, Version: plugin.Version, Type: "maven-plugin"}
+			queue = append(queue, queued{dep: pluginDep, path: path, root: m, anyVersion: plugin.Version == ""})
+			pluginPath := appendPath(path, artifactOf(pluginDep).String())
+			for _, dep := range plugin.Dependencies {
+				queue = append(queue, queued{dep: dep, path: pluginPath, exclusions: dep.Exclusions, root: m})
+			}
+		}
+	}
+
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		queue = append(queue, r.visit(closure, item, visited)...)
+	}
+	return closure, nil
+}
+
+// visit 处理一个依赖，返回它的传递依赖
+func (r *Resolver) visit(closure *Closure, item queued, visited map[string]bool) []queued {
+	dep := item.dep
+	if item.transitive {
+		// 项目的依赖管理覆盖传递依赖的版本
+		if managed, ok := item.root.managed[managementKey(dep)]; ok && managed.Version != "" {
+			dep.Version = managed.Version
+		}
+	}
+	artifact := artifactOf(dep)
+
+	if strings.Contains(artifact.GroupID+artifact.ArtifactID+artifact.Version, "${") {
+		closure.addProblem(Problem{Artifact: artifact, Path: item.path, Reason: "unresolved property in coordinates"})
+		return nil
+	}
+	if item.anyVersion || pom.IsVersionRange(dep.Version) {
+		return r.visitAllVersions(closure, item, artifact)
+	}
+	if dep.Version == "" {
+		closure.addProblem(Problem{Artifact: artifact, Path: item.path, Reason: "no version declared or managed"})
+		return nil
+	}
+
+	key := artifact.id() + "|" + exclusionsKey(item.exclusions)
+	if visited[key] {
+		return nil
+	}
+	visited[key] = true
+
+	closure.versions[artifact.Key()] = true
+	_, inReactor := r.reactor[artifact.Key()]
+	if !inReactor {
+		closure.addNode(artifact, item.path)
+	}
+
+	m, err := r.repositoryModel(artifact)
+	if err != nil {
+		closure.addProblem(Problem{Artifact: artifact, Path: item.path, Reason: err.Error()})
+		return nil
+	}
+	path := appendPath(item.path, artifact.String())
+	closure.addModelPOMs(m, path)
+
+	var children []queued
+	for _, child := range m.dependencies {
+		if child.Scope != "" && child.Scope != "compile" && child.Scope != "runtime" {
+			continue
+		}
+		if child.Optional == "true" || excluded(item.exclusions, child) {
+			continue
+		}
+		exclusions := append(append([]pom.Exclusion(nil), item.exclusions...), child.Exclusions...)
+		children = append(children, queued{dep: child, path: path, exclusions: exclusions, root: item.root, transitive: true})
+	}
+	return children
+}
+
+// visitAllVersions 版本未声明时闭包包含本地仓库中的所有版本，版本范围只包含落在范围内的本地版本
+//
+// 无法解析的版本范围按未声明版本处理，保留所有版本。
+func (r *Resolver) visitAllVersions(closure *Closure, item queued, artifact Artifact) []queued {
+	versions := r.localVersions(artifact.GroupID, artifact.ArtifactID)
+	reason := "no version is in the local repository"
+
+	vr, err := pom.ParseVersionRange(item.dep.Version)
+	if item.anyVersion || err != nil {
+		closure.anyVersions[artifact.GroupID+":"+artifact.ArtifactID] = true
+	} else {
+		versions = slices.DeleteFunc(versions, func(version string) bool { return !vr.Contains(version) })
+		reason = fmt.Sprintf("no version in %s is in the local repository", vr)
+	}
+	if len(versions) == 0 {
+		artifact.Version = item.dep.Version
+		closure.addProblem(Problem{Artifact: artifact, Path: item.path, Reason: reason, NotFound: true})
+		return nil
+	}
+
+	children := make([]queued, 0, len(versions))
+	for _, version := range versions {
+		next := item
+		next.dep.Version = version
+		next.transitive = false
+		next.anyVersion = false
+		children = append(children, next)
+	}
+	return children
+}
+
+// addModelPOMs 将构建模型用到的父 POM 和 BOM 加入闭包，并记录无法读取的 POM
+func (c *Closure) addModelPOMs(m *model, path []string) {
+	for _, artifact := range m.poms {
+		c.versions[artifact.Key()] = true
+		c.addNode(artifact, path)
+	}
+	for _, unavailable := range m.unavailable {
+		c.versions[unavailable.artifact.Key()] = true
+		c.addProblem(Problem{Artifact: unavailable.artifact, Path: path, Reason: fmt.Sprintf("%s POM: %v", unavailable.role, unavailable.err)})
+	}
+}
+
+// addNode 加入需要从本地仓库获取的构件，同一构件只记录第一次发现时的路径
+func (c *Closure) addNode(artifact Artifact, path []string) {
+	if c.nodes[artifact.id()] {
+		return
+	}
+	c.nodes[artifact.id()] = true
+	c.Nodes = append(c.Nodes, Node{Artifact: artifact, Path: path})
+}
+
+// addProblem 记录无法解析的构件，同一构件的相同问题只记录一次
+func (c *Closure) addProblem(problem Problem) {
+	key := problem.Artifact.id() + "|" + problem.Reason
+	if c.problems[key] {
+		return
+	}
+	c.problems[key] = true
+	c.Problems = append(c.Problems, problem)
+}
+
+// artifactOf 根据依赖的 type 和 classifier 确定构件文件的扩展名和分类器
+func artifactOf(dep pom.Dependency) Artifact {
+	artifact := Artifact{
+		GroupID:    dep.GroupID,
+		ArtifactID: dep.ArtifactID,
+		Version:    dep.Version,
+		Classifier: dep.Classifier,
+		Extension:  dep.Type,
+	}
+	if artifact.Extension == "" {
+		artifact.Extension = "jar"
+	}
+
+	defaultClassifiers := map[string]string{"test-jar": "tests", "ejb-client": "client", "java-source": "sources", "javadoc": "javadoc"}
+	if classifier, ok := defaultClassifiers[artifact.Extension]; ok {
+		if artifact.Classifier == "" {
+			artifact.Classifier = classifier
+		}
+		artifact.Extension = "jar"
+	} else if ext, ok := pom.ArtifactExtension(artifact.Extension); ok && ext != "" {
+		artifact.Extension = ext
+	}
+	return artifact
+}
+
+// excluded 判断依赖是否被排除，排除规则支持 * 通配符
+func excluded(exclusions []pom.Exclusion, dep pom.Dependency) bool {
+	for _, exclusion := range exclusions {
+		if (exclusion.GroupID == "*" || exclusion.GroupID == dep.GroupID) &&
+			(exclusion.ArtifactID == "*" || exclusion.ArtifactID == dep.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+// exclusionsKey 返回排除规则的规范化表示，用于区分经由不同排除规则到达的同一构件
+func exclusionsKey(exclusions []pom.Exclusion) string {
+	keys := make([]string, 0, len(exclusions))
+	for _, exclusion := range exclusions {
+		keys = append(keys, exclusion.GroupID+":"+exclusion.ArtifactID)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// appendPath 复制路径并追加一个节点，避免共享底层数组
+func appendPath(path []string, next string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), next)
+}
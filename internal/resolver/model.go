@@ -0,0 +1,422 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+)
+
+// propertyPattern 匹配 POM 中的 ${...} 属性引用
+var propertyPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// maxInterpolationDepth 属性嵌套展开的最大层数，防止循环引用
+const maxInterpolationDepth = 10
+
+// defaultPluginGroupID 插件未声明 groupId 时的默认值
+const defaultPluginGroupID = "org.apache.maven.plugins"
+
+// unavailablePOM 构建模型时无法读取的父 POM 或导入的 BOM
+type unavailablePOM struct {
+	artifact Artifact
+	role     string // parent 或 import
+	err      error
+}
+
+// model 合并了父 POM、属性和导入的 BOM 之后的有效 POM
+//
+// 依赖、依赖管理和插件以原始形式沿继承链累积，最后统一用当前项目的属性展开，
+// 这样子项目覆盖的属性也会作用于父 POM 中声明的版本，与 Maven 的行为一致。
+type model struct {
+	project       Artifact
+	packaging     string
+	parentVersion string
+	properties    map[string]string
+
+	rawManaged          []pom.Dependency
+	rawDependencies     []pom.Dependency
+	rawPlugins          []pom.Plugin
+	rawPluginManagement []pom.Plugin
+	rawExtensions       []pom.Plugin
+
+	managed      map[string]pom.Dependency // 键见 managementKey
+	dependencies []pom.Dependency
+	plugins      []pom.Plugin
+	extensions   []pom.Plugin
+
+	poms        []Artifact       // 构建该模型用到的父 POM 和 BOM
+	unavailable []unavailablePOM // 无法读取的父 POM 和 BOM
+}
+
+// buildModel 从解析后的 POM 构建有效模型，dir 为检出目录中 POM 所在的目录，本地仓库中的 POM 为空
+func (r *Resolver) buildModel(project *pom.Project, dir string) *model {
+	m := &model{
+		packaging:  project.EffectivePackaging(),
+		properties: make(map[string]string),
+		managed:    make(map[string]pom.Dependency),
+	}
+
+	if project.Parent != nil {
+		m.parentVersion = strings.TrimSpace(project.Parent.Version)
+		parentArtifact := Artifact{
+			GroupID:    strings.TrimSpace(project.Parent.GroupID),
+			ArtifactID: strings.TrimSpace(project.Parent.ArtifactID),
+			Version:    m.parentVersion,
+			Extension:  "pom",
+		}
+		parent, err := r.parentModel(parentArtifact, project.Parent.RelativePath, dir)
+		if err != nil {
+			m.unavailable = append(m.unavailable, unavailablePOM{artifact: parentArtifact, role: "parent", err: err})
+		} else {
+			m.inherit(parentArtifact, parent)
+		}
+	}
+
+	for name, value := range project.Properties {
+		m.properties[name] = value
+	}
+	m.rawManaged = append(m.rawManaged, project.DependencyManagement...)
+	m.rawDependencies = append(m.rawDependencies, project.Dependencies...)
+	m.rawPlugins = append(m.rawPlugins, project.Build.Plugins...)
+	m.rawPluginManagement = append(m.rawPluginManagement, project.Build.PluginManagement...)
+	m.rawExtensions = append(m.rawExtensions, project.Build.Extensions...)
+
+	// 坐标中的属性（如 ${revision}）在 project.* 属性中引用时再展开
+	m.project = Artifact{
+		GroupID:    project.EffectiveGroupID(),
+		ArtifactID: strings.TrimSpace(project.ArtifactID),
+		Version:    project.EffectiveVersion(),
+		Extension:  "pom",
+	}
+	m.project = Artifact{
+		GroupID:    m.interpolate(m.project.GroupID),
+		ArtifactID: m.interpolate(m.project.ArtifactID),
+		Version:    m.interpolate(m.project.Version),
+		Extension:  "pom",
+	}
+
+	r.effective(m)
+	return m
+}
+
+// inherit 继承父模型的属性、原始依赖和插件配置
+func (m *model) inherit(parentArtifact Artifact, parent *model) {
+	for name, value := range parent.properties {
+		m.properties[name] = value
+	}
+	m.rawManaged = append(m.rawManaged, parent.rawManaged...)
+	m.rawDependencies = append(m.rawDependencies, parent.rawDependencies...)
+	m.rawPlugins = append(m.rawPlugins, parent.rawPlugins...)
+	m.rawPluginManagement = append(m.rawPluginManagement, parent.rawPluginManagement...)
+	m.rawExtensions = append(m.rawExtensions, parent.rawExtensions...)
+	m.poms = append(m.poms, parentArtifact)
+	m.poms = append(m.poms, parent.poms...)
+	m.unavailable = append(m.unavailable, parent.unavailable...)
+}
+
+// effective 展开属性，合并依赖管理、导入的 BOM 和插件管理
+func (r *Resolver) effective(m *model) {
+	var imports []pom.Dependency
+	for _, dep := range m.rawManaged {
+		dep = m.interpolateDependency(dep)
+		if dep.Scope == "import" && dep.Type == "pom" {
+			imports = append(imports, dep)
+			continue
+		}
+		m.managed[managementKey(dep)] = dep
+	}
+
+	// 显式声明的依赖管理优先于导入的 BOM，多个 BOM 之间先声明的优先
+	for _, dep := range imports {
+		bomArtifact := Artifact{GroupID: dep.GroupID, ArtifactID: dep.ArtifactID, Version: dep.Version, Extension: "pom"}
+		bom, err := r.repositoryModel(bomArtifact)
+		if err != nil {
+			m.unavailable = append(m.unavailable, unavailablePOM{artifact: bomArtifact, role: "import", err: err})
+			continue
+		}
+		m.poms = append(m.poms, bomArtifact)
+		m.poms = append(m.poms, bom.poms...)
+		m.unavailable = append(m.unavailable, bom.unavailable...)
+		for key, managed := range bom.managed {
+			if _, ok := m.managed[key]; !ok {
+				m.managed[key] = managed
+			}
+		}
+	}
+
+	seen := make(map[string]int)
+	for _, dep := range m.rawDependencies {
+		dep = m.interpolateDependency(dep)
+		key := managementKey(dep)
+		if index, ok := seen[key]; ok {
+			m.dependencies[index] = dep
+			continue
+		}
+		seen[key] = len(m.dependencies)
+		m.dependencies = append(m.dependencies, dep)
+	}
+	for i := range m.dependencies {
+		m.manage(&m.dependencies[i])
+	}
+
+	pluginManagement := make(map[string]pom.Plugin)
+	for _, plugin := range m.rawPluginManagement {
+		plugin = m.interpolatePlugin(plugin)
+		pluginManagement[plugin.GroupID+":"+plugin.ArtifactID] = plugin
+	}
+	m.plugins = mergePlugins(m, m.rawPlugins, pluginManagement)
+	m.extensions = mergePlugins(m, m.rawExtensions, pluginManagement)
+
+	// 默认生命周期插件没有在 POM 中声明，版本只能来自插件管理
+	declared := make(map[string]bool)
+	for _, plugin := range m.plugins {
+		declared[plugin.GroupID+":"+plugin.ArtifactID] = true
+	}
+	for _, artifactID := range lifecyclePlugins(m.packaging) {
+		key := defaultPluginGroupID + ":" + artifactID
+		if declared[key] {
+			continue
+		}
+		plugin := pom.Plugin{GroupID: defaultPluginGroupID, ArtifactID: artifactID}
+		if managed, ok := pluginManagement[key]; ok {
+			plugin = managed
+		}
+		m.plugins = append(m.plugins, plugin)
+	}
+}
+
+// mergePlugins 展开插件声明，子 POM 中的声明覆盖父 POM，缺少的版本和依赖从插件管理中补充
+func mergePlugins(m *model, raw []pom.Plugin, pluginManagement map[string]pom.Plugin) []pom.Plugin {
+	var plugins []pom.Plugin
+	seen := make(map[string]int)
+	for _, plugin := range raw {
+		plugin = m.interpolatePlugin(plugin)
+		key := plugin.GroupID + ":" + plugin.ArtifactID
+		index, ok := seen[key]
+		if !ok {
+			seen[key] = len(plugins)
+			plugins = append(plugins, plugin)
+			continue
+		}
+		// raw 中父 POM 的声明在前，子 POM 未声明的版本和依赖沿用父 POM
+		inherited := plugins[index]
+		if plugin.Version == "" {
+			plugin.Version = inherited.Version
+		}
+		if len(plugin.Dependencies) == 0 {
+			plugin.Dependencies = inherited.Dependencies
+		}
+		plugins[index] = plugin
+	}
+
+	for i, plugin := range plugins {
+		managed, ok := pluginManagement[plugin.GroupID+":"+plugin.ArtifactID]
+		if !ok {
+			continue
+		}
+		if plugin.Version == "" {
+			plugins[i].Version = managed.Version
+		}
+		if len(plugin.Dependencies) == 0 {
+			plugins[i].Dependencies = managed.Dependencies
+		}
+	}
+	return plugins
+}
+
+// manage 用依赖管理补充依赖的版本和作用域
+func (m *model) manage(dep *pom.Dependency) {
+	managed, ok := m.managed[managementKey(*dep)]
+	if !ok {
+		return
+	}
+	if dep.Version == "" {
+		dep.Version = managed.Version
+	}
+	if dep.Scope == "" {
+		dep.Scope = managed.Scope
+	}
+	if len(dep.Exclusions) == 0 {
+		dep.Exclusions = managed.Exclusions
+	}
+}
+
+// interpolateDependency 展开依赖中的属性并填充默认的 type
+func (m *model) interpolateDependency(dep pom.Dependency) pom.Dependency {
+	dep.GroupID = m.interpolate(dep.GroupID)
+	dep.ArtifactID = m.interpolate(dep.ArtifactID)
+	dep.Version = m.interpolate(dep.Version)
+	dep.Type = m.interpolate(dep.Type)
+	if dep.Type == "" {
+		dep.Type = "jar"
+	}
+	dep.Classifier = m.interpolate(dep.Classifier)
+	dep.Scope = m.interpolate(dep.Scope)
+	dep.Optional = m.interpolate(dep.Optional)
+	exclusions := make([]pom.Exclusion, 0, len(dep.Exclusions))
+	for _, exclusion := range dep.Exclusions {
+		exclusions = append(exclusions, pom.Exclusion{
+			GroupID:    m.interpolate(exclusion.GroupID),
+			ArtifactID: m.interpolate(exclusion.ArtifactID),
+		})
+	}
+	dep.Exclusions = exclusions
+	return dep
+}
+
+// interpolatePlugin 展开插件中的属性并填充默认的 groupId
+func (m *model) interpolatePlugin(plugin pom.Plugin) pom.Plugin {
+	plugin.GroupID = m.interpolate(plugin.GroupID)
+	if plugin.GroupID == "" {
+		plugin.GroupID = defaultPluginGroupID
+	}
+	plugin.ArtifactID = m.interpolate(plugin.ArtifactID)
+	plugin.Version = m.interpolate(plugin.Version)
+	dependencies := make([]pom.Dependency, 0, len(plugin.Dependencies))
+	for _, dep := range plugin.Dependencies {
+		dependencies = append(dependencies, m.interpolateDependency(dep))
+	}
+	plugin.Dependencies = dependencies
+	return plugin
+}
+
+// interpolate 展开值中的 ${...} 属性引用，无法展开的引用原样保留
+func (m *model) interpolate(value string) string {
+	value = strings.TrimSpace(value)
+	for depth := 0; depth < maxInterpolationDepth && strings.Contains(value, "${"); depth++ {
+		expanded := propertyPattern.ReplaceAllStringFunc(value, func(expr string) string {
+			if resolved, ok := m.property(expr[2 : len(expr)-1]); ok {
+				return resolved
+			}
+			return expr
+		})
+		if expanded == value {
+			break
+		}
+		value = expanded
+	}
+	return value
+}
+
+// property 查找属性值，支持 project.*、parent.*、env.* 和 <properties> 中声明的属性
+func (m *model) property(name string) (string, bool) {
+	switch name {
+	case "project.groupId", "pom.groupId", "groupId":
+		return m.project.GroupID, true
+	case "project.artifactId", "pom.artifactId", "artifactId":
+		return m.project.ArtifactID, true
+	case "project.version", "pom.version", "version":
+		return m.project.Version, true
+	case "project.packaging", "pom.packaging":
+		return m.packaging, true
+	case "project.parent.version", "parent.version":
+		return m.parentVersion, m.parentVersion != ""
+	}
+	if env, ok := strings.CutPrefix(name, "env."); ok {
+		return os.LookupEnv(env)
+	}
+	value, ok := m.properties[name]
+	return value, ok
+}
+
+// managementKey 依赖管理中的键 "groupId:artifactId:type:classifier"
+func managementKey(dep pom.Dependency) string {
+	depType := dep.Type
+	if depType == "" {
+		depType = "jar"
+	}
+	return dep.GroupID + ":" + dep.ArtifactID + ":" + depType + ":" + dep.Classifier
+}
+
+// lifecyclePluginsByPackaging 各打包类型默认生命周期（到 install 阶段）绑定的插件
+var lifecyclePluginsByPackaging = map[string][]string{
+	"jar":          {"maven-resources-plugin", "maven-compiler-plugin", "maven-surefire-plugin", "maven-jar-plugin", "maven-install-plugin"},
+	"war":          {"maven-resources-plugin", "maven-compiler-plugin", "maven-surefire-plugin", "maven-war-plugin", "maven-install-plugin"},
+	"maven-plugin": {"maven-plugin-plugin", "maven-resources-plugin", "maven-compiler-plugin", "maven-surefire-plugin", "maven-jar-plugin", "maven-install-plugin"},
+	"pom":          {"maven-install-plugin"},
+}
+
+// lifecyclePlugins 返回打包类型默认绑定的插件，未知打包类型按 jar 处理
+func lifecyclePlugins(packaging string) []string {
+	if plugins, ok := lifecyclePluginsByPackaging[packaging]; ok {
+		return plugins
+	}
+	return lifecyclePluginsByPackaging["jar"]
+}
+
+// parentModel 加载父 POM，优先使用检出目录中 relativePath 指向且坐标一致的 POM
+func (r *Resolver) parentModel(parent Artifact, relativePath, dir string) (*model, error) {
+	if dir != "" {
+		if relativePath = strings.TrimSpace(relativePath); relativePath == "" {
+			relativePath = filepath.Join("..", "pom.xml")
+		}
+		path := filepath.Join(dir, relativePath)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pom.xml")
+		}
+		if project, err := pom.ParseFile(path); err == nil &&
+			project.EffectiveGroupID() == parent.GroupID &&
+			strings.TrimSpace(project.ArtifactID) == parent.ArtifactID &&
+			project.EffectiveVersion() == parent.Version {
+			return r.projectModel(path)
+		}
+	}
+	return r.repositoryModel(parent)
+}
+
+// projectModel 加载检出目录中的 POM
+func (r *Resolver) projectModel(path string) (*model, error) {
+	key := "file:" + path
+	if m, ok := r.models[key]; ok {
+		return m, nil
+	}
+	if r.building[key] {
+		return nil, fmt.Errorf("cyclic parent reference at %s", path)
+	}
+
+	project, err := pom.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r.building[key] = true
+	m := r.buildModel(project, filepath.Dir(path))
+	delete(r.building, key)
+	r.models[key] = m
+	return m, nil
+}
+
+// repositoryModel 加载本地仓库中的 POM，属于反应堆的项目从检出目录加载
+func (r *Resolver) repositoryModel(artifact Artifact) (*model, error) {
+	if path, ok := r.reactor[artifact.Key()]; ok {
+		return r.projectModel(path)
+	}
+
+	key := artifact.Key()
+	if m, ok := r.models[key]; ok {
+		return m, nil
+	}
+	if err, ok := r.failed[key]; ok {
+		return nil, err
+	}
+	if r.building[key] {
+		return nil, fmt.Errorf("cyclic parent or import reference at %s", key)
+	}
+
+	path := r.PomPath(artifact)
+	project, err := pom.ParseFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("%s is not in the local repository", filepath.Base(path))
+		}
+		r.failed[key] = err
+		return nil, err
+	}
+	r.building[key] = true
+	m := r.buildModel(project, "")
+	delete(r.building, key)
+	r.models[key] = m
+	return m, nil
+}
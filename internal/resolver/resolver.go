@@ -0,0 +1,328 @@
+// Package resolver 从项目 POM 出发，只使用本地仓库中的 POM 计算依赖闭包
+package resolver
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+	"github.com/lyj404/clean-mvn/internal/repository"
+)
+
+// Artifact 依赖闭包中的一个构件
+type Artifact struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string
+	Extension  string
+}
+
+// Key 返回 "groupId:artifactId:version"
+func (a Artifact) Key() string {
+	return a.GroupID + ":" + a.ArtifactID + ":" + a.Version
+}
+
+// String 返回 Maven 风格的坐标，扩展名为 jar 且没有分类器时省略这两部分，版本未知时省略版本
+func (a Artifact) String() string {
+	parts := []string{a.GroupID, a.ArtifactID}
+	if a.Classifier != "" {
+		parts = append(parts, a.Extension, a.Classifier)
+	} else if a.Extension != "" && a.Extension != "jar" {
+		parts = append(parts, a.Extension)
+	}
+	if a.Version != "" {
+		parts = append(parts, a.Version)
+	}
+	return strings.Join(parts, ":")
+}
+
+// id 区分同一版本中不同分类器和扩展名的构件
+func (a Artifact) id() string {
+	return a.Key() + ":" + a.Extension + ":" + a.Classifier
+}
+
+// Resolver 只使用本地仓库中的 POM 解析项目的依赖闭包
+type Resolver struct {
+	trees    []string          // 按坐标组织构件的目录树，分离布局中依次为 installed 和 cached 的子树
+	reactor  map[string]string // "groupId:artifactId:version" -> 检出目录中的 POM 路径
+	models   map[string]*model
+	failed   map[string]error
+	building map[string]bool // 正在构建的模型，用于发现循环引用
+}
+
+// NewResolver 创建使用指定本地仓库的解析器，支持 Maven 3.9 的分离布局
+func NewResolver(repo string) *Resolver {
+	var trees []string
+	for _, tree := range repository.Trees(repo) {
+		trees = append(trees, tree.Path)
+	}
+	return &Resolver{
+		trees:    trees,
+		reactor:  make(map[string]string),
+		models:   make(map[string]*model),
+		failed:   make(map[string]error),
+		building: make(map[string]bool),
+	}
+}
+
+// VersionDir 返回构件版本目录在本地仓库中的路径
+//
+// 分离布局中返回第一个包含该版本目录的子树中的路径，都不存在时使用第一棵子树。
+func (r *Resolver) VersionDir(a Artifact) string {
+	for _, tree := range r.trees {
+		if dir := versionDir(tree, a); isDir(dir) {
+			return dir
+		}
+	}
+	return versionDir(r.trees[0], a)
+}
+
+// versionDir 返回构件版本目录在目录树中的路径
+func versionDir(tree string, a Artifact) string {
+	parts := append([]string{tree}, strings.Split(a.GroupID, ".")...)
+	return filepath.Join(append(parts, a.ArtifactID, a.Version)...)
+}
+
+// isDir 判断路径是否为目录
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// PomPath 返回构件的 POM 在本地仓库中的路径
+func (r *Resolver) PomPath(a Artifact) string {
+	pomArtifact := a
+	pomArtifact.Classifier = ""
+	pomArtifact.Extension = "pom"
+	return r.ArtifactPath(pomArtifact)
+}
+
+// ArtifactPath 返回构件文件在本地仓库中的路径
+//
+// 快照版本优先使用 "-SNAPSHOT" 命名的文件，不存在时使用最新的带时间戳的文件。
+func (r *Resolver) ArtifactPath(a Artifact) string {
+	suffix := "." + a.Extension
+	if a.Classifier != "" {
+		suffix = "-" + a.Classifier + suffix
+	}
+	dir := r.VersionDir(a)
+	path := filepath.Join(dir, a.ArtifactID+"-"+a.Version+suffix)
+
+	base, ok := strings.CutSuffix(a.Version, "-SNAPSHOT")
+	if !ok {
+		return path
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, a.ArtifactID+"-"+base+"-*"+suffix))
+	var timestamped []string
+	prefix := a.ArtifactID + "-" + base + "-"
+	for _, match := range matches {
+		// 排除分类器不同但后缀相同的文件，例如 foo-1.0-20260101.120000-1-tests.jar 之于 foo-1.0-*.jar
+		rest := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), prefix), suffix)
+		if strings.Count(rest, "-") == 1 {
+			timestamped = append(timestamped, match)
+		}
+	}
+	if len(timestamped) == 0 {
+		return path
+	}
+	sort.Strings(timestamped)
+	return timestamped[len(timestamped)-1]
+}
+
+// localVersions 返回本地仓库中某个构件的所有版本目录名，分离布局中合并所有子树
+func (r *Resolver) localVersions(groupID, artifactID string) []string {
+	var versions []string
+	for _, tree := range r.trees {
+		dir := filepath.Dir(versionDir(tree, Artifact{GroupID: groupID, ArtifactID: artifactID, Version: "_"}))
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !slices.Contains(versions, entry.Name()) {
+				versions = append(versions, entry.Name())
+			}
+		}
+	}
+	return versions
+}
+
+// loadReactor 读取项目目录或 POM 文件，递归加载其中声明的模块，返回反应堆中的 POM 路径
+func (r *Resolver) loadReactor(path string, seen map[string]bool) ([]string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "pom.xml")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if seen[path] {
+		return nil, nil
+	}
+	seen[path] = true
+
+	project, err := pom.ParseFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read project POM: %w", err)
+	}
+	m, err := r.projectModel(path)
+	if err != nil {
+		return nil, err
+	}
+	r.reactor[m.project.Key()] = path
+
+	paths := []string{path}
+	for _, module := range project.Modules {
+		modulePaths, err := r.loadReactor(filepath.Join(filepath.Dir(path), strings.TrimSpace(module)), seen)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, modulePaths...)
+	}
+	return paths, nil
+}
+
+// ReverseIndex 由本地仓库中所有 POM 构建的反向依赖索引
+type ReverseIndex struct {
+	dependents  map[string][]string // 被依赖构件的 "groupId:artifactId:version" -> 依赖它的构件
+	unversioned map[string][]string // 被依赖构件的 "groupId:artifactId" -> 声明依赖但无法确定版本的构件
+}
+
+// ReverseIndex 解析本地仓库中的每个 POM，记录依赖、父 POM 和导入的 BOM 到依赖方的反向关系
+//
+// 测试作用域的依赖不会影响其他构件的使用，不计入索引。版本范围只对本地落在范围内的版本生效；
+// 无法确定版本的依赖单独记录，只用于报告，不参与影响的传递。
+func (r *Resolver) ReverseIndex() (*ReverseIndex, error) {
+	index := &ReverseIndex{dependents: make(map[string][]string), unversioned: make(map[string][]string)}
+	edges := make(map[string]bool)
+	indexed := make(map[string]bool)
+	addTo := func(m map[string][]string, key, dependent string) {
+		edge := key + "|" + dependent
+		if key == dependent || edges[edge] {
+			return
+		}
+		edges[edge] = true
+		m[key] = append(m[key], dependent)
+	}
+	add := func(key, dependent string) {
+		addTo(index.dependents, key, dependent)
+	}
+
+	for _, tree := range r.trees {
+		err := filepath.WalkDir(tree, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".pom") {
+				return nil
+			}
+			artifact, ok := coordinatesOf(tree, filepath.Dir(path))
+			if !ok || indexed[artifact.Key()] {
+				return nil
+			}
+			indexed[artifact.Key()] = true
+
+			m, err := r.repositoryModel(artifact)
+			if err != nil {
+				return nil
+			}
+			dependent := artifact.Key()
+			for _, parent := range m.poms {
+				add(parent.Key(), dependent)
+			}
+			for _, dep := range m.dependencies {
+				if dep.Scope == "test" {
+					continue
+				}
+				key := dep.GroupID + ":" + dep.ArtifactID
+				if dep.Version == "" {
+					addTo(index.unversioned, key, dependent)
+					continue
+				}
+				if !pom.IsVersionRange(dep.Version) {
+					add(key+":"+dep.Version, dependent)
+					continue
+				}
+				versions, err := pom.ParseVersionRange(dep.Version)
+				if err != nil {
+					addTo(index.unversioned, key, dependent)
+					continue
+				}
+				for _, version := range r.localVersions(dep.GroupID, dep.ArtifactID) {
+					if versions.Contains(version) {
+						add(key+":"+version, dependent)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return index, nil
+}
+
+// coordinatesOf 根据目录树中版本目录的路径推断坐标
+func coordinatesOf(tree, dir string) (Artifact, bool) {
+	rel, err := filepath.Rel(tree, dir)
+	if err != nil {
+		return Artifact{}, false
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	n := len(segments)
+	if n < 3 || segments[0] == ".." {
+		return Artifact{}, false
+	}
+	return Artifact{
+		GroupID:    strings.Join(segments[:n-2], "."),
+		ArtifactID: segments[n-2],
+		Version:    segments[n-1],
+		Extension:  "pom",
+	}, true
+}
+
+// Dependents 返回直接依赖某个版本的构件，包括版本范围覆盖该版本的依赖方
+func (idx *ReverseIndex) Dependents(groupID, artifactID, version string) []string {
+	dependents := append([]string(nil), idx.dependents[groupID+":"+artifactID+":"+version]...)
+	sort.Strings(dependents)
+	return dependents
+}
+
+// Unversioned 返回声明依赖某个构件但无法确定版本的构件，它们不一定使用某个具体的本地版本
+func (idx *ReverseIndex) Unversioned(groupID, artifactID string) []string {
+	dependents := append([]string(nil), idx.unversioned[groupID+":"+artifactID]...)
+	sort.Strings(dependents)
+	return dependents
+}
+
+// Impact 返回直接依赖和间接依赖某个版本的构件，间接依赖不包含直接依赖
+func (idx *ReverseIndex) Impact(groupID, artifactID, version string) (direct, transitive []string) {
+	direct = idx.Dependents(groupID, artifactID, version)
+	visited := map[string]bool{groupID + ":" + artifactID + ":" + version: true}
+	for _, dependent := range direct {
+		visited[dependent] = true
+	}
+
+	queue := append([]string(nil), direct...)
+	for len(queue) > 0 {
+		parts := strings.SplitN(queue[0], ":", 3)
+		queue = queue[1:]
+		for _, dependent := range idx.Dependents(parts[0], parts[1], parts[2]) {
+			if visited[dependent] {
+				continue
+			}
+			visited[dependent] = true
+			transitive = append(transitive, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+	sort.Strings(transitive)
+	return direct, transitive
+}
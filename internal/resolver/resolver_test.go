@@ -0,0 +1,286 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeRepoPOM 在本地仓库中写入 POM，body 为 <project> 中坐标之后的内容
+func writeRepoPOM(t *testing.T, repo, groupID, artifactID, version, body string) {
+	t.Helper()
+	path := filepath.Join(repo, strings.ReplaceAll(groupID, ".", "/"), artifactID, version, artifactID+"-"+version+".pom")
+	writeFile(t, path, "<project><groupId>"+groupID+"</groupId><artifactId>"+artifactID+"</artifactId><version>"+version+"</version>"+body+"</project>")
+}
+
+// setupProject 创建一个包含父 POM、模块、BOM 导入和插件的项目及对应的本地仓库
+func setupProject(t *testing.T) (repo, project string) {
+	t.Helper()
+	repo = t.TempDir()
+	project = t.TempDir()
+
+	writeFile(t, filepath.Join(project, "pom.xml"), `<project>
+  <groupId>com.acme</groupId><artifactId>root</artifactId><version>${revision}</version><packaging>pom</packaging>
+  <modules><module>app</module></modules>
+  <properties><revision>1.0-SNAPSHOT</revision><trans.version>1.0</trans.version></properties>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.bom</groupId><artifactId>bom</artifactId><version>1.0</version><type>pom</type><scope>import</scope></dependency>
+    <dependency><groupId>org.trans</groupId><artifactId>trans</artifactId><version>${trans.version}</version></dependency>
+  </dependencies></dependencyManagement>
+  <build><pluginManagement><plugins>
+    <plugin><artifactId>maven-compiler-plugin</artifactId><version>3.11.0</version></plugin>
+  </plugins></pluginManagement></build>
+</project>`)
+	writeFile(t, filepath.Join(project, "app", "pom.xml"), `<project>
+  <parent><groupId>com.acme</groupId><artifactId>root</artifactId><version>1.0-SNAPSHOT</version></parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency><groupId>org.lib</groupId><artifactId>lib</artifactId>
+      <exclusions><exclusion><groupId>org.ex</groupId><artifactId>*</artifactId></exclusion></exclusions>
+    </dependency>
+    <dependency><groupId>org.junit</groupId><artifactId>junit</artifactId><version>4.13</version><scope>test</scope></dependency>
+    <dependency><groupId>org.missing</groupId><artifactId>gone</artifactId><version>1.0</version></dependency>
+  </dependencies>
+</project>`)
+
+	writeRepoPOM(t, repo, "org.bom", "bom-parent", "1", "")
+	writeRepoPOM(t, repo, "org.bom", "bom", "1.0", `<parent><groupId>org.bom</groupId><artifactId>bom-parent</artifactId><version>1</version></parent>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.lib</groupId><artifactId>lib</artifactId><version>2.0</version></dependency>
+  </dependencies></dependencyManagement>`)
+	writeRepoPOM(t, repo, "org.lib", "lib", "1.0", "")
+	writeRepoPOM(t, repo, "org.lib", "lib", "2.0", `<dependencies>
+    <dependency><groupId>org.trans</groupId><artifactId>trans</artifactId><version>0.9</version></dependency>
+    <dependency><groupId>org.opt</groupId><artifactId>opt</artifactId><version>1.0</version><optional>true</optional></dependency>
+    <dependency><groupId>org.lib</groupId><artifactId>lib-test</artifactId><version>1.0</version><scope>test</scope></dependency>
+    <dependency><groupId>org.ex</groupId><artifactId>excluded</artifactId><version>1.0</version></dependency>
+  </dependencies>`)
+	writeRepoPOM(t, repo, "org.trans", "trans", "0.9", "")
+	writeRepoPOM(t, repo, "org.trans", "trans", "1.0", "")
+	writeRepoPOM(t, repo, "org.opt", "opt", "1.0", "")
+	writeRepoPOM(t, repo, "org.lib", "lib-test", "1.0", "")
+	writeRepoPOM(t, repo, "org.ex", "excluded", "1.0", "")
+	writeRepoPOM(t, repo, "org.junit", "junit", "4.13", "")
+	writeRepoPOM(t, repo, "org.apache.maven.plugins", "maven-compiler-plugin", "3.11.0", `<dependencies>
+    <dependency><groupId>org.plexus</groupId><artifactId>utils</artifactId><version>3.0</version></dependency>
+  </dependencies>`)
+	writeRepoPOM(t, repo, "org.apache.maven.plugins", "maven-compiler-plugin", "3.8.1", "")
+	writeRepoPOM(t, repo, "org.plexus", "utils", "3.0", "")
+	writeRepoPOM(t, repo, "org.apache.maven.plugins", "maven-install-plugin", "3.1.1", "")
+	writeRepoPOM(t, repo, "org.apache.maven.plugins", "maven-install-plugin", "2.5.2", "")
+	return repo, project
+}
+
+func TestResolve(t *testing.T) {
+	repo, project := setupProject(t)
+
+	closure, err := NewResolver(repo).Resolve([]string{project})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	var projects []string
+	for _, p := range closure.Projects {
+		projects = append(projects, p.Key())
+	}
+	if want := []string{"com.acme:root:1.0-SNAPSHOT", "com.acme:app:1.0-SNAPSHOT"}; !slices.Equal(projects, want) {
+		t.Errorf("Projects = %v, want %v", projects, want)
+	}
+
+	tests := []struct {
+		coordinates string
+		want        bool
+	}{
+		{"com.acme:app:1.0-SNAPSHOT", true},
+		{"org.bom:bom:1.0", true},
+		{"org.bom:bom-parent:1", true},
+		{"org.lib:lib:2.0", true},
+		{"org.lib:lib:1.0", false},
+		// 项目的依赖管理覆盖传递依赖声明的版本
+		{"org.trans:trans:0.9", false},
+		{"org.trans:trans:1.0", true},
+		{"org.opt:opt:1.0", false},
+		{"org.lib:lib-test:1.0", false},
+		{"org.ex:excluded:1.0", false},
+		{"org.junit:junit:4.13", true},
+		{"org.apache.maven.plugins:maven-compiler-plugin:3.11.0", true},
+		{"org.apache.maven.plugins:maven-compiler-plugin:3.8.1", false},
+		{"org.plexus:utils:3.0", true},
+		// 未在插件管理中声明版本的生命周期插件保留所有本地版本
+		{"org.apache.maven.plugins:maven-install-plugin:2.5.2", true},
+		{"org.apache.maven.plugins:maven-install-plugin:3.1.1", true},
+	}
+	for _, tt := range tests {
+		parts := strings.Split(tt.coordinates, ":")
+		if got := closure.Contains(parts[0], parts[1], parts[2]); got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.coordinates, got, tt.want)
+		}
+	}
+
+	var missing *Problem
+	for i, problem := range closure.Problems {
+		if problem.Artifact.Key() == "org.missing:gone:1.0" {
+			missing = &closure.Problems[i]
+		}
+	}
+	if missing == nil {
+		t.Fatalf("Problems = %+v, want org.missing:gone:1.0", closure.Problems)
+	}
+	if want := []string{"com.acme:app:1.0-SNAPSHOT"}; !slices.Equal(missing.Path, want) {
+		t.Errorf("missing artifact Path = %v, want %v", missing.Path, want)
+	}
+}
+
+func TestResolveMissingProject(t *testing.T) {
+	if _, err := NewResolver(t.TempDir()).Resolve([]string{t.TempDir()}); err == nil {
+		t.Error("Resolve() error = nil, want error for a directory without pom.xml")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	m := &model{
+		project:    Artifact{GroupID: "org.example", ArtifactID: "foo", Version: "1.0"},
+		properties: map[string]string{"a": "${b}", "b": "value", "loop": "${loop}"},
+	}
+	tests := map[string]string{
+		"${project.version}":      "1.0",
+		"${pom.groupId}.${a}":     "org.example.value",
+		"${unknown}":              "${unknown}",
+		"${loop}":                 "${loop}",
+		" ${project.artifactId} ": "foo",
+	}
+	for value, want := range tests {
+		if got := m.interpolate(value); got != want {
+			t.Errorf("interpolate(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestArtifactPath(t *testing.T) {
+	repo := t.TempDir()
+	r := NewResolver(repo)
+	dir := filepath.Join(repo, "org", "example", "foo", "1.0-SNAPSHOT")
+	for _, name := range []string{
+		"foo-1.0-20260101.120000-1.jar",
+		"foo-1.0-20260102.120000-2.jar",
+		"foo-1.0-20260103.120000-3-tests.jar",
+	} {
+		writeFile(t, filepath.Join(dir, name), "")
+	}
+
+	snapshot := Artifact{GroupID: "org.example", ArtifactID: "foo", Version: "1.0-SNAPSHOT", Extension: "jar"}
+	if got, want := r.ArtifactPath(snapshot), filepath.Join(dir, "foo-1.0-20260102.120000-2.jar"); got != want {
+		t.Errorf("ArtifactPath() = %s, want %s", got, want)
+	}
+	release := Artifact{GroupID: "org.example", ArtifactID: "foo", Version: "1.0", Classifier: "sources", Extension: "jar"}
+	if got, want := r.ArtifactPath(release), filepath.Join(repo, "org", "example", "foo", "1.0", "foo-1.0-sources.jar"); got != want {
+		t.Errorf("ArtifactPath() = %s, want %s", got, want)
+	}
+}
+
+func TestReverseIndex(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoPOM(t, repo, "org.example", "parent", "1", "")
+	writeRepoPOM(t, repo, "org.example", "core", "1.0", `<parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>1</version></parent>`)
+	writeRepoPOM(t, repo, "org.example", "mid", "1.0", `<dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>core</artifactId><version>1.0</version></dependency>
+  </dependencies>`)
+	writeRepoPOM(t, repo, "org.example", "ranged", "1.0", `<dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>core</artifactId><version>[1.0,2.0)</version></dependency>
+  </dependencies>`)
+	writeRepoPOM(t, repo, "org.example", "app", "1.0", `<dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>mid</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.example</groupId><artifactId>parent</artifactId><version>1</version><scope>test</scope><type>pom</type></dependency>
+  </dependencies>`)
+	writeRepoPOM(t, repo, "org.example", "core", "2.0", "")
+	writeRepoPOM(t, repo, "org.example", "newer", "1.0", `<dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>core</artifactId><version>[2.0,)</version></dependency>
+  </dependencies>`)
+	writeRepoPOM(t, repo, "org.example", "loose", "1.0", `<dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>core</artifactId></dependency>
+  </dependencies>`)
+
+	index, err := NewResolver(repo).ReverseIndex()
+	if err != nil {
+		t.Fatalf("ReverseIndex() error = %v", err)
+	}
+
+	tests := []struct {
+		artifact       string
+		wantDirect     []string
+		wantTransitive []string
+	}{
+		{"org.example:core:1.0", []string{"org.example:mid:1.0", "org.example:ranged:1.0"}, []string{"org.example:app:1.0"}},
+		{"org.example:parent:1", []string{"org.example:core:1.0"}, []string{"org.example:mid:1.0", "org.example:ranged:1.0", "org.example:app:1.0"}},
+		{"org.example:app:1.0", nil, nil},
+		// 版本范围只覆盖范围内的本地版本，没有版本的依赖不参与传递
+		{"org.example:core:2.0", []string{"org.example:newer:1.0"}, nil},
+	}
+	for _, tt := range tests {
+		parts := strings.Split(tt.artifact, ":")
+		direct, transitive := index.Impact(parts[0], parts[1], parts[2])
+		if !slices.Equal(direct, tt.wantDirect) {
+			t.Errorf("Impact(%s) direct = %v, want %v", tt.artifact, direct, tt.wantDirect)
+		}
+		slices.Sort(tt.wantTransitive)
+		if !slices.Equal(transitive, tt.wantTransitive) {
+			t.Errorf("Impact(%s) transitive = %v, want %v", tt.artifact, transitive, tt.wantTransitive)
+		}
+	}
+	if got, want := index.Unversioned("org.example", "core"), []string{"org.example:loose:1.0"}; !slices.Equal(got, want) {
+		t.Errorf("Unversioned(org.example:core) = %v, want %v", got, want)
+	}
+}
+
+func TestSplitLayout(t *testing.T) {
+	root := t.TempDir()
+	installed := filepath.Join(root, "installed")
+	cached := filepath.Join(root, "cached", "central")
+	writeRepoPOM(t, installed, "com.acme", "parent", "1.0", "")
+	writeRepoPOM(t, cached, "org.lib", "lib", "1.0", `<parent><groupId>com.acme</groupId><artifactId>parent</artifactId><version>1.0</version></parent>`)
+	writeFile(t, filepath.Join(cached, "org", "lib", "lib", "1.0", "_remote.repositories"), "lib-1.0.pom>central=\n")
+	writeRepoPOM(t, cached, "org.lib", "lib", "2.0", "")
+	writeFile(t, filepath.Join(installed, "org", "lib", "lib", "3.0-SNAPSHOT", "lib-3.0-SNAPSHOT.pom"), "<project/>")
+
+	r := NewResolver(root)
+	tests := []struct {
+		artifact Artifact
+		want     string
+	}{
+		{Artifact{GroupID: "com.acme", ArtifactID: "parent", Version: "1.0"}, filepath.Join(installed, "com", "acme", "parent", "1.0")},
+		{Artifact{GroupID: "org.lib", ArtifactID: "lib", Version: "1.0"}, filepath.Join(cached, "org", "lib", "lib", "1.0")},
+		// 不存在的版本目录使用第一棵子树
+		{Artifact{GroupID: "org.lib", ArtifactID: "lib", Version: "9.9"}, filepath.Join(installed, "org", "lib", "lib", "9.9")},
+	}
+	for _, tt := range tests {
+		if got := r.VersionDir(tt.artifact); got != tt.want {
+			t.Errorf("VersionDir(%s) = %s, want %s", tt.artifact.Key(), got, tt.want)
+		}
+	}
+
+	versions := r.localVersions("org.lib", "lib")
+	slices.Sort(versions)
+	if want := []string{"1.0", "2.0", "3.0-SNAPSHOT"}; !slices.Equal(versions, want) {
+		t.Errorf("localVersions() = %v, want %v", versions, want)
+	}
+
+	index, err := r.ReverseIndex()
+	if err != nil {
+		t.Fatalf("ReverseIndex() error = %v", err)
+	}
+	if got, want := index.Dependents("com.acme", "parent", "1.0"), []string{"org.lib:lib:1.0"}; !slices.Equal(got, want) {
+		t.Errorf("Dependents(com.acme:parent:1.0) = %v, want %v", got, want)
+	}
+}
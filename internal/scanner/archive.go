@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// archiveExtensions 需要校验 ZIP 结构的构件扩展名
+var archiveExtensions = []string{".jar", ".war", ".ear", ".aar", ".zip"}
+
+// CorruptArchiveDetector 查找无法打开或条目无法读取的 jar/war/ear/aar/zip 构件
+//
+// 下载中断留下的截断文件不会产生 .lastUpdated，只能通过校验 ZIP 中央目录和各条目的 CRC 发现。
+type CorruptArchiveDetector struct{}
+
+// NewCorruptArchiveDetector 创建损坏归档检测器
+func NewCorruptArchiveDetector() *CorruptArchiveDetector {
+	return &CorruptArchiveDetector{}
+}
+
+// Name 返回检测器名称
+func (cd *CorruptArchiveDetector) Name() string {
+	return "corrupt-jar"
+}
+
+// Match 判断是否为需要校验的归档文件
+func (cd *CorruptArchiveDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && IsArchive(d.Name())
+}
+
+// Detect 校验归档文件，损坏时标记其所在的版本目录
+func (cd *CorruptArchiveDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	err := VerifyArchive(path)
+	if err == nil {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(path)
+	result := types.Result{
+		Path:   dir,
+		Scope:  scopeOf(dir),
+		Reason: fmt.Sprintf("corrupt archive %s: %v", d.Name(), err),
+	}
+	if result.Scope != types.ScopeVersion {
+		result.Files = []string{path}
+	}
+	return []types.Result{result}, nil
+}
+
+// VerifyArchive 打开 ZIP 归档并完整读取每个条目，返回遇到的第一个错误
+func VerifyArchive(path string) error {
+	file, err := atime.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	reader, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	for _, file := range reader.File {
+		if err := verifyArchiveEntry(file); err != nil {
+			return fmt.Errorf("entry %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// verifyArchiveEntry 读取单个条目，读取到末尾时 archive/zip 会校验 CRC32
+func verifyArchiveEntry(file *zip.File) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
+
+// IsArchive 判断文件名是否为 ZIP 格式的构件
+func IsArchive(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, archiveExt := range archiveExtensions {
+		if ext == archiveExt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+// buildArchive 生成包含一个条目的 ZIP 内容
+func buildArchive(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyArchive(t *testing.T) {
+	valid := buildArchive(t, "META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n")
+	badCRC := bytes.Replace(valid, []byte("Manifest-Version"), []byte("Manifest-Versiom"), 1)
+
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr bool
+	}{
+		{"valid archive", valid, false},
+		{"truncated archive", valid[:len(valid)/2], true},
+		{"not an archive", []byte("<html>Login required</html>"), true},
+		{"corrupt entry", badCRC, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "foo-1.0.jar")
+			os.WriteFile(path, tt.content, 0644)
+
+			err := VerifyArchive(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCorruptArchiveDetector(t *testing.T) {
+	root := t.TempDir()
+	good := filepath.Join(root, "org", "example", "good", "1.0")
+	bad := filepath.Join(root, "org", "example", "bad", "1.0")
+	writeFile(t, filepath.Join(good, "good-1.0.jar"), string(buildArchive(t, "a.txt", "a")))
+	writeFile(t, filepath.Join(bad, "bad-1.0.jar"), "PK\x03\x04 truncated")
+	writeFile(t, filepath.Join(bad, "bad-1.0.pom"), "<project/>")
+
+	s := NewScanner(logger.NewCustomLogger(), NewCorruptArchiveDetector())
+	result := s.ScanRepository(scanConfig(root))
+
+	if len(result.Results) != 1 {
+		t.Fatalf("ScanRepository() found %d results, want 1: %v", len(result.Results), result.Results)
+	}
+	if result.Results[0].Path != bad || result.Results[0].Detector != "corrupt-jar" {
+		t.Errorf("result = %+v, want %s flagged by corrupt-jar", result.Results[0], bad)
+	}
+	if result.Results[0].Reason == "" {
+		t.Error("result Reason should contain the ZIP error")
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"foo-1.0.jar", true},
+		{"foo-1.0.war", true},
+		{"foo-1.0.ear", true},
+		{"foo-1.0.aar", true},
+		{"foo-1.0-bin.ZIP", true},
+		{"foo-1.0.pom", false},
+		{"foo-1.0.jar.sha1", false},
+	}
+	for _, tt := range tests {
+		if got := IsArchive(tt.name); got != tt.want {
+			t.Errorf("IsArchive(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
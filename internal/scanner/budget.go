@@ -0,0 +1,161 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// BudgetDetectorName 大小预算检测器名称
+const BudgetDetectorName = "budget"
+
+// budgetCandidate 可以为满足大小预算而删除的版本目录
+type budgetCandidate struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+	snapshot bool
+}
+
+// BudgetDetector 在仓库超出目标大小时按最近最少使用的顺序删除版本目录，直到仓库能够放入预算
+//
+// 扫描过程中只记录每个版本目录的大小和最后使用时间，在 Finalize 中扣除其他检测器已计划删除的部分后
+// 再决定删除哪些目录。快照版本优先于正式版本删除，同类目录中最后使用时间早的先删除。
+type BudgetDetector struct {
+	maxSize int64
+
+	mu         sync.Mutex
+	candidates []budgetCandidate
+}
+
+// NewBudgetDetector 创建大小预算检测器，maxSize 为仓库的目标大小（字节）
+func NewBudgetDetector(maxSize int64) *BudgetDetector {
+	return &BudgetDetector{maxSize: maxSize}
+}
+
+// Name 返回检测器名称
+func (bd *BudgetDetector) Name() string {
+	return BudgetDetectorName
+}
+
+// Match 所有目录都可能是版本目录，具体判断在 Detect 中进行
+func (bd *BudgetDetector) Match(path string, d fs.DirEntry) bool {
+	return d.IsDir()
+}
+
+// Detect 记录版本目录的大小和最后使用时间，结果在 Finalize 中生成
+func (bd *BudgetDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	if !isVersionDir(path) {
+		return nil, nil
+	}
+	lastUsed, size, err := directoryUsage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bd.mu.Lock()
+	bd.candidates = append(bd.candidates, budgetCandidate{
+		path:     path,
+		size:     size,
+		lastUsed: lastUsed,
+		snapshot: strings.HasSuffix(d.Name(), "-SNAPSHOT"),
+	})
+	bd.mu.Unlock()
+	return nil, nil
+}
+
+// adopt 接管另一个检测器记录的版本目录，用于对同一仓库的多棵子树统一计算预算
+func (bd *BudgetDetector) adopt(other *BudgetDetector) {
+	other.mu.Lock()
+	candidates := other.candidates
+	other.candidates = nil
+	other.mu.Unlock()
+
+	bd.mu.Lock()
+	bd.candidates = append(bd.candidates, candidates...)
+	bd.mu.Unlock()
+}
+
+// Finalize 按淘汰顺序追加版本目录，直到删除所有结果后的仓库大小不超过预算
+func (bd *BudgetDetector) Finalize(result *types.ScanResult) {
+	bd.mu.Lock()
+	candidates := bd.candidates
+	bd.candidates = nil
+	bd.mu.Unlock()
+
+	result.SizeBudget = bd.maxSize
+	result.ProjectedSize = result.RepositorySize - result.TotalSize
+	if result.ProjectedSize <= bd.maxSize {
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].snapshot != candidates[j].snapshot {
+			return candidates[i].snapshot
+		}
+		if !candidates[i].lastUsed.Equal(candidates[j].lastUsed) {
+			return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+		}
+		return candidates[i].path < candidates[j].path
+	})
+
+	byPath := make(map[string]int, len(result.Results))
+	wholeDirs := make(map[string]int)
+	for i, res := range result.Results {
+		byPath[res.Path] = i
+		if len(res.Files) == 0 {
+			wholeDirs[res.Path] = i
+		}
+	}
+
+	for _, candidate := range candidates {
+		if result.ProjectedSize <= bd.maxSize {
+			break
+		}
+		if _, ok := scheduledAncestor(wholeDirs, candidate.path); ok {
+			continue
+		}
+		reason := "evicted to fit the size budget, least recently used"
+		if !candidate.lastUsed.IsZero() {
+			reason = fmt.Sprintf("evicted to fit the size budget, last used %s", candidate.lastUsed.Format("2006-01-02"))
+		}
+
+		index, ok := byPath[candidate.path]
+		if !ok {
+			result.Results = append(result.Results, types.Result{
+				Path:     candidate.path,
+				Size:     candidate.size,
+				Detector: BudgetDetectorName,
+				Scope:    types.ScopeVersion,
+				Reason:   reason,
+				LastUsed: candidate.lastUsed,
+			})
+			result.TotalSize += candidate.size
+			result.ProjectedSize -= candidate.size
+			continue
+		}
+
+		// 已有文件级结果的目录改为整目录删除
+		existing := &result.Results[index]
+		if len(existing.Files) == 0 {
+			continue
+		}
+		freed := candidate.size - existing.Size
+		existing.Files = nil
+		existing.Size = candidate.size
+		existing.Detector += "," + BudgetDetectorName
+		existing.Reason = strings.TrimPrefix(existing.Reason+"; "+reason, "; ")
+		existing.LastUsed = candidate.lastUsed
+		result.TotalSize += freed
+		result.ProjectedSize -= freed
+	}
+
+	sort.Slice(result.Results, func(i, j int) bool {
+		return result.Results[i].Path < result.Results[j].Path
+	})
+}
@@ -0,0 +1,174 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+func TestBudgetDetector(t *testing.T) {
+	now := time.Now()
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	tests := []struct {
+		name          string
+		maxSize       int64
+		withTemp      bool
+		wantPaths     []string
+		wantDetectors []string
+		wantProjected int64
+	}{
+		{
+			name:          "within budget",
+			maxSize:       1000,
+			wantProjected: 450,
+		},
+		{
+			name:          "snapshots before least recently used releases",
+			maxSize:       260,
+			wantPaths:     []string{"1.0", "2.1-SNAPSHOT"},
+			wantDetectors: []string{"budget", "budget"},
+			wantProjected: 250,
+		},
+		{
+			name:          "upgrades file-level results",
+			maxSize:       200,
+			withTemp:      true,
+			wantPaths:     []string{"1.0", "2.0", "2.1-SNAPSHOT"},
+			wantDetectors: []string{"budget", "temp,budget", "budget"},
+			wantProjected: 150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			artifact := filepath.Join(root, "org", "example", "foo")
+			content := strings.Repeat("x", 100)
+			for version, used := range map[string]time.Time{
+				"1.0":          days(300),
+				"2.0":          days(200),
+				"3.0":          now,
+				"2.1-SNAPSHOT": now,
+			} {
+				path := filepath.Join(artifact, version, "foo-"+version+".jar")
+				writeFile(t, path, content)
+				os.Chtimes(path, used, used)
+			}
+			writeFile(t, filepath.Join(artifact, "maven-metadata.xml"), strings.Repeat("m", 50))
+
+			detectors := []Detector{NewBudgetDetector(tt.maxSize)}
+			if tt.withTemp {
+				part := filepath.Join(artifact, "2.0", "foo-2.0.jar.part")
+				writeFile(t, part, strings.Repeat("p", 10))
+				os.Chtimes(part, days(200), days(200))
+				detectors = []Detector{NewTempDetector(time.Hour), NewBudgetDetector(tt.maxSize)}
+			}
+
+			config := scanConfig(root)
+			config.MaxSize = tt.maxSize
+			s := NewScanner(logger.NewCustomLogger(), detectors...)
+			result := s.ScanRepository(config)
+
+			var paths, names []string
+			for _, res := range result.Results {
+				paths = append(paths, filepath.Base(res.Path))
+				names = append(names, res.Detector)
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Errorf("result paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if !slices.Equal(names, tt.wantDetectors) {
+				t.Errorf("result detectors = %v, want %v", names, tt.wantDetectors)
+			}
+			if result.SizeBudget != tt.maxSize {
+				t.Errorf("SizeBudget = %d, want %d", result.SizeBudget, tt.maxSize)
+			}
+			if result.ProjectedSize != tt.wantProjected {
+				t.Errorf("ProjectedSize = %d, want %d", result.ProjectedSize, tt.wantProjected)
+			}
+			if result.RepositorySize-result.TotalSize != result.ProjectedSize {
+				t.Errorf("RepositorySize %d - TotalSize %d != ProjectedSize %d",
+					result.RepositorySize, result.TotalSize, result.ProjectedSize)
+			}
+			for _, res := range result.Results {
+				if strings.Contains(res.Detector, "budget") && (len(res.Files) != 0 || res.Scope != types.ScopeVersion) {
+					t.Errorf("budget result %s should remove the whole version directory", res.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestScanRootsSplitBudget(t *testing.T) {
+	now := time.Now()
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	root := t.TempDir()
+	content := strings.Repeat("x", 100)
+	versions := map[string]time.Time{
+		filepath.Join("installed", "org", "example", "app", "1.0", "app-1.0.jar"):         days(500),
+		filepath.Join("cached", "central", "org", "example", "foo", "1.0", "foo-1.0.jar"): days(300),
+		filepath.Join("cached", "central", "org", "example", "foo", "2.0", "foo-2.0.jar"): days(10),
+		filepath.Join("cached", "mycorp", "com", "mycorp", "bar", "1.0", "bar-1.0.jar"):   days(400),
+		filepath.Join("cached", "mycorp", "com", "mycorp", "bar", "2.0", "bar-2.0.jar"):   now,
+	}
+	for rel, used := range versions {
+		path := filepath.Join(root, rel)
+		writeFile(t, path, content)
+		os.Chtimes(path, used, used)
+	}
+
+	// 每棵子树单独只有 200 字节，不会超出预算；加上受保护的 installed/ 后整个仓库为 500 字节
+	var scanners []*Scanner
+	var configs []types.ScanConfig
+	for _, id := range []string{"central", "mycorp"} {
+		config := types.ScanConfig{
+			InputPath:               filepath.Join(root, "cached", id),
+			MaxConcurrentGoRoutines: 2,
+			MaxSize:                 300,
+			Tree:                    types.Tree{Path: filepath.Join(root, "cached", id), Kind: types.TreeCached, RepositoryID: id},
+			RepositoryRoot:          root,
+			ProtectedTrees:          []string{filepath.Join(root, "installed")},
+		}
+		detectors, err := NewDetectors(nil, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanners = append(scanners, NewScanner(logger.NewCustomLogger(), detectors...))
+		configs = append(configs, config)
+	}
+	result := ScanRoots(scanners, configs)
+
+	var got []string
+	for _, res := range result.Results {
+		rel, _ := filepath.Rel(root, res.Path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := []string{"cached/central/org/example/foo/1.0", "cached/mycorp/com/mycorp/bar/1.0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanRoots() evicted %v, want %v", got, want)
+	}
+	if len(result.Repositories) != 1 {
+		t.Fatalf("ScanRoots() Repositories = %+v, want one repository", result.Repositories)
+	}
+	repo := result.Repositories[0]
+	if repo.Root != root || repo.RepositorySize != 500 || repo.ProjectedSize != 300 || repo.Count != 2 {
+		t.Errorf("repository summary = %+v, want %s with size 500 projected to 300", repo, root)
+	}
+	if result.SizeBudget != 300 || result.ProjectedSize != 300 || result.RepositorySize != 500 {
+		t.Errorf("ScanRoots() size %d, projected %d, budget %d, want 500, 300, 300",
+			result.RepositorySize, result.ProjectedSize, result.SizeBudget)
+	}
+	for i, summary := range result.Roots {
+		if summary.Count != 1 {
+			t.Errorf("Roots[%d] = %+v, want one eviction per tree", i, summary)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// ChecksumDetector 将构件的实际摘要与旁边的 .sha512/.sha256/.sha1/.md5 校验文件比对
+//
+// 哈希计算在扫描器的工作池中进行，并发数即 ScanConfig.MaxConcurrentGoRoutines。
+// 没有任何校验文件的构件不会被标记，而是汇总到 ScanResult.MissingChecksums 中。
+type ChecksumDetector struct {
+	mu      sync.Mutex
+	missing []string
+}
+
+// NewChecksumDetector 创建校验和检测器
+func NewChecksumDetector() *ChecksumDetector {
+	return &ChecksumDetector{}
+}
+
+// Name 返回检测器名称
+func (cd *ChecksumDetector) Name() string {
+	return "checksum"
+}
+
+// Match 判断是否为需要校验的构件文件
+func (cd *ChecksumDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && isArtifactFile(path)
+}
+
+// Detect 计算构件摘要并与所有存在的校验文件比对，不一致时标记其所在的版本目录
+func (cd *ChecksumDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	expected := make(map[string]string)
+	for _, ext := range checksumExtensions {
+		digest, err := readChecksumFile(path + ext)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		// 空的校验文件没有可比对的摘要，与缺失同等对待
+		if digest == "" {
+			continue
+		}
+		expected[strings.TrimPrefix(ext, ".")] = digest
+	}
+
+	if len(expected) == 0 {
+		cd.mu.Lock()
+		cd.missing = append(cd.missing, path)
+		cd.mu.Unlock()
+		return nil, nil
+	}
+
+	actual, err := computeDigests(path, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []types.ChecksumMismatch
+	for _, ext := range checksumExtensions {
+		algorithm := strings.TrimPrefix(ext, ".")
+		if want, ok := expected[algorithm]; ok && want != actual[algorithm] {
+			mismatches = append(mismatches, types.ChecksumMismatch{
+				File:      path,
+				Algorithm: algorithm,
+				Expected:  want,
+				Actual:    actual[algorithm],
+			})
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(path)
+	return []types.Result{{
+		Path:       dir,
+		Scope:      scopeOf(dir),
+		Reason:     fmt.Sprintf("checksum mismatch for %s (%s)", d.Name(), mismatches[0].Algorithm),
+		Mismatches: mismatches,
+	}}, nil
+}
+
+// Finalize 将没有校验文件的构件写入扫描结果
+func (cd *ChecksumDetector) Finalize(result *types.ScanResult) {
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+
+	sort.Strings(cd.missing)
+	result.MissingChecksums = append(result.MissingChecksums, cd.missing...)
+	cd.missing = nil
+}
+
+// readChecksumFile 读取校验文件中的摘要，兼容 "<摘要>  <文件名>" 格式，文件为空时返回空字符串
+func readChecksumFile(path string) (string, error) {
+	content, err := atime.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// computeDigests 读取一次文件，同时计算所需算法的十六进制摘要
+func computeDigests(path string, algorithms map[string]string) (map[string]string, error) {
+	hashes := make(map[string]hash.Hash)
+	writers := make([]io.Writer, 0, len(algorithms))
+	for algorithm := range algorithms {
+		h := newHash(algorithm)
+		hashes[algorithm] = h
+		writers = append(writers, h)
+	}
+
+	file, err := atime.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(io.MultiWriter(writers...), file); err != nil {
+		return nil, err
+	}
+
+	digests := make(map[string]string, len(hashes))
+	for algorithm, h := range hashes {
+		digests[algorithm] = hex.EncodeToString(h.Sum(nil))
+	}
+	return digests, nil
+}
+
+// newHash 按算法名称创建哈希
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "sha512":
+		return sha512.New()
+	case "sha256":
+		return sha256.New()
+	case "md5":
+		return md5.New()
+	default:
+		return sha1.New()
+	}
+}
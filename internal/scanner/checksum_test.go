@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestChecksumDetector(t *testing.T) {
+	root := t.TempDir()
+	artifact := filepath.Join(root, "org", "example", "foo")
+	good := filepath.Join(artifact, "1.0", "foo-1.0.jar")
+	bad := filepath.Join(artifact, "1.1", "foo-1.1.jar")
+	unchecked := filepath.Join(artifact, "1.2", "foo-1.2.jar")
+	// 空的校验文件视为缺失，不能当作摘要不一致而删除构件
+	empty := filepath.Join(artifact, "1.3", "foo-1.3.jar")
+	partial := filepath.Join(artifact, "1.4", "foo-1.4.jar")
+
+	sum := sha1.Sum([]byte("good content"))
+	writeFile(t, good, "good content")
+	writeFile(t, good+".sha1", hex.EncodeToString(sum[:])+"  foo-1.0.jar\n")
+	writeFile(t, bad, "truncated")
+	writeFile(t, bad+".sha1", hex.EncodeToString(sum[:]))
+	writeFile(t, bad+".md5", "d41d8cd98f00b204e9800998ecf8427e")
+	writeFile(t, unchecked, "installed locally")
+	writeFile(t, empty, "downloaded")
+	writeFile(t, empty+".sha1", " \n")
+	md5sum := md5.Sum([]byte("downloaded"))
+	writeFile(t, partial, "downloaded")
+	writeFile(t, partial+".sha1", "")
+	writeFile(t, partial+".md5", hex.EncodeToString(md5sum[:]))
+
+	s := NewScanner(logger.NewCustomLogger(), NewChecksumDetector())
+	result := s.ScanRepository(scanConfig(root))
+
+	if len(result.Results) != 1 {
+		t.Fatalf("ScanRepository() found %d results, want 1: %v", len(result.Results), result.Results)
+	}
+	res := result.Results[0]
+	if res.Path != filepath.Dir(bad) {
+		t.Errorf("result Path = %s, want %s", res.Path, filepath.Dir(bad))
+	}
+	if len(res.Mismatches) != 2 {
+		t.Fatalf("result has %d mismatches, want 2: %v", len(res.Mismatches), res.Mismatches)
+	}
+	if res.Mismatches[0].Algorithm != "sha1" || res.Mismatches[0].Expected != hex.EncodeToString(sum[:]) {
+		t.Errorf("Mismatches[0] = %+v", res.Mismatches[0])
+	}
+
+	if want := []string{unchecked, empty}; !slices.Equal(result.MissingChecksums, want) {
+		t.Errorf("MissingChecksums = %v, want %v", result.MissingChecksums, want)
+	}
+}
+
+func TestIsArtifactFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/repo/org/foo/1.0/foo-1.0.jar", true},
+		{"/repo/org/foo/1.0/foo-1.0-sources.jar", true},
+		{"/repo/org/foo/1.0/foo-1.0.pom", true},
+		{"/repo/org/foo/1.0/foo-1.0.jar.sha1", false},
+		{"/repo/org/foo/1.0/foo-1.0.jar.asc", false},
+		{"/repo/org/foo/1.0/foo-1.0.jar.lastUpdated", false},
+		{"/repo/org/foo/1.0/_remote.repositories", false},
+		{"/repo/org/foo/maven-metadata-central.xml", false},
+		{"/repo/archetype-catalog.xml", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isArtifactFile(tt.path); got != tt.want {
+				t.Errorf("isArtifactFile(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// 内容嗅探识别出的文件类型
+const (
+	contentEmpty  = "empty"
+	contentZip    = "application/zip"
+	contentGzip   = "application/gzip"
+	contentXML    = "application/xml"
+	contentHTML   = "text/html"
+	contentJSON   = "application/json"
+	contentText   = "text/plain"
+	contentBinary = "application/octet-stream"
+)
+
+// sniffLength 内容嗅探读取的字节数
+const sniffLength = 512
+
+// ContentDetector 通过魔数识别被代理服务器的登录页、404 页面等替换掉的构件
+//
+// jar/war/aar/zip/ear 必须是 ZIP 格式，pom 必须是 XML，任何构件都不应是 HTML 页面。
+type ContentDetector struct{}
+
+// NewContentDetector 创建内容嗅探检测器
+func NewContentDetector() *ContentDetector {
+	return &ContentDetector{}
+}
+
+// Name 返回检测器名称
+func (cd *ContentDetector) Name() string {
+	return "content"
+}
+
+// Match 判断是否为需要嗅探的构件文件
+func (cd *ContentDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && isArtifactFile(path)
+}
+
+// Detect 读取文件头部识别内容类型，与扩展名不符时标记其所在的版本目录
+func (cd *ContentDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	head, err := readHead(path, sniffLength)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := sniffContentType(head)
+	expected := expectedContentType(d.Name())
+	if contentType != contentHTML && (expected == "" || contentType == expected) {
+		return nil, nil
+	}
+
+	reason := fmt.Sprintf("%s is %s", d.Name(), contentType)
+	if expected != "" {
+		reason += ", expected " + expected
+	}
+
+	dir := filepath.Dir(path)
+	return []types.Result{{
+		Path:        dir,
+		Scope:       scopeOf(dir),
+		Reason:      reason,
+		ContentType: contentType,
+	}}, nil
+}
+
+// expectedContentType 根据扩展名返回构件应有的内容类型，未知扩展名返回空字符串
+func expectedContentType(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jar", ".war", ".aar", ".zip", ".ear":
+		return contentZip
+	case ".pom", ".xml":
+		return contentXML
+	}
+	return ""
+}
+
+// sniffContentType 根据文件头部的魔数和文本特征识别内容类型
+func sniffContentType(head []byte) string {
+	if len(head) == 0 {
+		return contentEmpty
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")),
+		bytes.HasPrefix(head, []byte("PK\x05\x06")),
+		bytes.HasPrefix(head, []byte("PK\x07\x08")):
+		return contentZip
+	case bytes.HasPrefix(head, []byte("\x1f\x8b")):
+		return contentGzip
+	}
+
+	text := bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	text = bytes.TrimLeft(text, " \t\r\n")
+	lower := bytes.ToLower(text)
+
+	// 跳过 XML 声明，代理返回的 XHTML 页面也会带有声明
+	if bytes.HasPrefix(lower, []byte("<?xml")) {
+		end := bytes.Index(lower, []byte("?>"))
+		if end < 0 {
+			return contentXML
+		}
+		lower = bytes.TrimLeft(lower[end+2:], " \t\r\n")
+		if len(lower) == 0 {
+			return contentXML
+		}
+	}
+
+	for _, prefix := range []string{"<!doctype html", "<html", "<head", "<body"} {
+		if bytes.HasPrefix(lower, []byte(prefix)) {
+			return contentHTML
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(lower, []byte("<")):
+		return contentXML
+	case bytes.HasPrefix(lower, []byte("{")) || bytes.HasPrefix(lower, []byte("[")):
+		return contentJSON
+	case isText(text):
+		return contentText
+	}
+	return contentBinary
+}
+
+// isText 判断内容是否全部为可打印文本
+func isText(data []byte) bool {
+	for _, c := range data {
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// readHead 读取文件开头最多 n 个字节
+func readHead(path string, n int) ([]byte, error) {
+	file, err := atime.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, n)
+	read, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return head[:read], nil
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestSniffContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"empty", "", contentEmpty},
+		{"zip", "PK\x03\x04\x14\x00", contentZip},
+		{"gzip", "\x1f\x8b\x08\x00", contentGzip},
+		{"pom with declaration", "<?xml version=\"1.0\"?>\n<project>", contentXML},
+		{"pom without declaration", "\xef\xbb\xbf  <project xmlns=\"http://maven.apache.org/POM/4.0.0\">", contentXML},
+		{"html doctype", "<!DOCTYPE html>\n<html><body>Login</body></html>", contentHTML},
+		{"xhtml with declaration", "<?xml version=\"1.0\"?>\n<html xmlns=\"http://www.w3.org/1999/xhtml\">", contentHTML},
+		{"json", "{\"errors\":[{\"status\":404}]}", contentJSON},
+		{"text", "404 Not Found", contentText},
+		{"binary", "\x00\x01\x02\x03", contentBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffContentType([]byte(tt.head)); got != tt.want {
+				t.Errorf("sniffContentType(%q) = %v, want %v", tt.head, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentDetector(t *testing.T) {
+	root := t.TempDir()
+	artifact := filepath.Join(root, "org", "example", "foo")
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.jar"), "PK\x03\x04")
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.pom"), "<project/>")
+	writeFile(t, filepath.Join(artifact, "1.1", "foo-1.1.jar"), "<!DOCTYPE html><html>Please log in</html>")
+	writeFile(t, filepath.Join(artifact, "1.2", "foo-1.2.pom"), "404 Not Found")
+	writeFile(t, filepath.Join(artifact, "1.3", "foo-1.3.module"), "<html><body>Proxy error</body></html>")
+
+	s := NewScanner(logger.NewCustomLogger(), NewContentDetector())
+	result := s.ScanRepository(scanConfig(root))
+
+	want := map[string]string{
+		filepath.Join(artifact, "1.1"): contentHTML,
+		filepath.Join(artifact, "1.2"): contentText,
+		filepath.Join(artifact, "1.3"): contentHTML,
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("ScanRepository() found %d results, want %d: %v", len(result.Results), len(want), result.Results)
+	}
+	for _, res := range result.Results {
+		if res.ContentType != want[res.Path] {
+			t.Errorf("result %s ContentType = %q, want %q", res.Path, res.ContentType, want[res.Path])
+		}
+		if res.Reason == "" {
+			t.Errorf("result %s should explain the content mismatch", res.Path)
+		}
+	}
+}
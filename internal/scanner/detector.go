@@ -0,0 +1,303 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// Detector 检测器，扫描器会对遍历到的每个文件或目录运行所有检测器
+type Detector interface {
+	// Name 返回检测器名称，记录在 types.Result.Detector 中
+	Name() string
+	// Match 在遍历过程中同步调用，只应做文件名之类的廉价判断
+	Match(path string, d fs.DirEntry) bool
+	// Detect 在工作池中调用，对 Match 命中的条目做进一步检查并返回需要清理的结果
+	Detect(root, path string, d fs.DirEntry) ([]types.Result, error)
+}
+
+// Finalizer 可选接口，检测器在扫描结束后向扫描结果补充汇总信息
+type Finalizer interface {
+	// Finalize 在所有结果合并并计算大小之后调用，调用后检测器应清空本次扫描收集的状态
+	Finalize(result *types.ScanResult)
+}
+
+// ResolverStatusFile Maven 3.9 记录元数据更新状态的文件
+const ResolverStatusFile = "resolver-status.properties"
+
+// LastUpdatedDetector 查找包含 .lastUpdated 文件或记录了失败的 resolver-status.properties 的目录
+type LastUpdatedDetector struct{}
+
+// NewLastUpdatedDetector 创建 .lastUpdated 检测器
+func NewLastUpdatedDetector() *LastUpdatedDetector {
+	return &LastUpdatedDetector{}
+}
+
+// Name 返回检测器名称
+func (ld *LastUpdatedDetector) Name() string {
+	return "lastupdated"
+}
+
+// Match 判断是否为下载失败标记文件
+func (ld *LastUpdatedDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && (strings.HasSuffix(d.Name(), ".lastUpdated") || d.Name() == ResolverStatusFile)
+}
+
+// Detect 根据标记文件所在的层级确定清理目标，并附带文件中记录的下载失败信息
+//
+// 元数据（maven-metadata-*.xml）的失败只清理标记文件和过期的元数据文件；
+// 版本目录中构件的失败清理整个版本目录；位于 groupId 或 artifactId 目录中的
+// 其他标记文件只清理标记本身，避免误删该构件的所有版本。
+func (ld *LastUpdatedDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	if d.Name() == ResolverStatusFile {
+		return ld.detectResolverStatus(path)
+	}
+
+	dir := filepath.Dir(path)
+	result := types.Result{Path: dir, Scope: scopeOf(dir), Markers: []string{path}}
+
+	switch {
+	case isMetadataFile(d.Name()):
+		metadata := strings.TrimSuffix(path, ".lastUpdated")
+		result.Files = append([]string{path}, existingFiles(metadata, metadata+".sha1", metadata+".md5")...)
+	case result.Scope != types.ScopeVersion:
+		result.Files = []string{path}
+	}
+
+	// 内容无法解析时仍然标记该目录，只是缺少失败详情
+	if props, err := parsePropertiesFile(path); err == nil {
+		result.Failures = parseFailures(props)
+	}
+
+	return []types.Result{result}, nil
+}
+
+// detectResolverStatus 检查 resolver-status.properties 中是否记录了元数据下载失败
+//
+// 该文件以 "<元数据文件名>/<仓库键>.error" 的形式记录失败，属于元数据层级，
+// 因此只清理该文件和对应的过期元数据文件。
+func (ld *LastUpdatedDetector) detectResolverStatus(path string) ([]types.Result, error) {
+	props, err := parsePropertiesFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	files := []string{path}
+	hasError := false
+	for key := range props {
+		if !strings.HasSuffix(key, ".error") {
+			continue
+		}
+		hasError = true
+		if name, _, ok := strings.Cut(key, "/"); ok && isMetadataFile(name) {
+			for _, file := range existingFiles(filepath.Join(dir, name)) {
+				if !slices.Contains(files, file) {
+					files = append(files, file)
+				}
+			}
+		}
+	}
+	if !hasError {
+		return nil, nil
+	}
+
+	return []types.Result{{
+		Path:     dir,
+		Scope:    scopeOf(dir),
+		Files:    files,
+		Markers:  []string{path},
+		Failures: parseFailures(props),
+	}}, nil
+}
+
+// StripResolverStatusFailures 读取 resolver-status.properties，去掉其中记录了失败的条目
+//
+// 每个 "<键>.error" 连同同一键的 "<键>.lastUpdated" 算作一条失败记录，其他仓库和元数据文件的条目原样保留。
+// 返回去掉失败记录后的内容和去掉的记录数；没有剩余条目时返回的内容为 nil，表示应删除整个文件。
+func StripResolverStatusFailures(path string) ([]byte, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	lines, err := readPropertyLines(file)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	failed := make(map[string]bool)
+	for _, line := range lines {
+		if key, ok := strings.CutSuffix(line.key, ".error"); ok && line.entry {
+			failed[key] = true
+		}
+	}
+
+	var kept strings.Builder
+	entries := 0
+	for _, line := range lines {
+		if line.entry {
+			key, ok := strings.CutSuffix(line.key, ".error")
+			if !ok {
+				key, ok = strings.CutSuffix(line.key, ".lastUpdated")
+			}
+			if ok && failed[key] {
+				continue
+			}
+			entries++
+		}
+		kept.WriteString(line.raw)
+		kept.WriteByte('\n')
+	}
+	if entries == 0 {
+		return nil, len(failed), nil
+	}
+	return []byte(kept.String()), len(failed), nil
+}
+
+// MarkerCount 返回清理一个失败标记时计入的标记数，预览和实际清理使用相同的计数
+//
+// resolver-status.properties 只去掉其中的失败条目，按失败记录计数；其余标记文件各计一个。
+func MarkerCount(path string) int {
+	if filepath.Base(path) != ResolverStatusFile {
+		return 1
+	}
+	_, removed, err := StripResolverStatusFailures(path)
+	if err != nil {
+		return 0
+	}
+	return removed
+}
+
+// parseFailures 从 .lastUpdated 属性中提取每个远程仓库的失败记录
+//
+// Maven Resolver 以 "<仓库键>.lastUpdated=<毫秒时间戳>" 和 "<仓库键>.error=<错误信息>"
+// 的形式记录失败，仓库键通常是仓库 URL，也可能带有认证摘要、仓库 ID 等前缀。
+func parseFailures(props map[string]string) []types.Failure {
+	failuresMap := make(map[string]*types.Failure)
+	failure := func(key string) *types.Failure {
+		repository := repositoryFromKey(key)
+		if f, ok := failuresMap[repository]; ok {
+			return f
+		}
+		f := &types.Failure{Repository: repository}
+		failuresMap[repository] = f
+		return f
+	}
+
+	for key, value := range props {
+		switch {
+		case strings.HasSuffix(key, ".error"):
+			failure(strings.TrimSuffix(key, ".error")).Error = value
+		case strings.HasSuffix(key, ".lastUpdated"):
+			millis, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			failure(strings.TrimSuffix(key, ".lastUpdated")).LastAttempt = time.UnixMilli(millis)
+		}
+	}
+
+	failures := make([]types.Failure, 0, len(failuresMap))
+	for _, f := range failuresMap {
+		failures = append(failures, *f)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Repository < failures[j].Repository
+	})
+	return failures
+}
+
+// ReadFailureMarker 读取 .lastUpdated 文件中记录的下载失败
+func ReadFailureMarker(path string) ([]types.Failure, error) {
+	props, err := parsePropertiesFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFailures(props), nil
+}
+
+// repositoryFromKey 从仓库键中提取远程仓库 URL，去掉认证摘要和仓库 ID 等前缀
+func repositoryFromKey(key string) string {
+	idx := strings.Index(key, "://")
+	if idx <= 0 {
+		return key
+	}
+
+	// 向前回溯到 URL 协议名的起始位置
+	start := idx
+	for start > 0 && isSchemeChar(key[start-1]) {
+		start--
+	}
+	return key[start:]
+}
+
+// isSchemeChar 判断字符是否可以出现在 URL 协议名中
+func isSchemeChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '+' || c == '.'
+}
+
+// detectorFactories 内置检测器，按名称创建
+var detectorFactories = map[string]func(config types.ScanConfig) Detector{
+	"lastupdated": func(types.ScanConfig) Detector { return NewLastUpdatedDetector() },
+	"corrupt-jar": func(types.ScanConfig) Detector { return NewCorruptArchiveDetector() },
+	"checksum":    func(types.ScanConfig) Detector { return NewChecksumDetector() },
+	"content":     func(types.ScanConfig) Detector { return NewContentDetector() },
+	"pom":         func(types.ScanConfig) Detector { return NewPomDetector() },
+	"temp":        func(config types.ScanConfig) Detector { return NewTempDetector(config.TempMinAge) },
+	"incomplete":  func(types.ScanConfig) Detector { return NewIncompleteDetector() },
+}
+
+// DetectorNames 返回所有内置检测器的名称
+func DetectorNames() []string {
+	names := make([]string, 0, len(detectorFactories))
+	for name := range detectorFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewDetectors 按名称创建内置检测器，并追加扫描配置中启用的保留策略检测器
+func NewDetectors(names []string, config types.ScanConfig) ([]Detector, error) {
+	detectors := make([]Detector, 0, len(names))
+	for _, name := range names {
+		factory, ok := detectorFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector %q (available: %s)", name, strings.Join(DetectorNames(), ", "))
+		}
+		detectors = append(detectors, factory(config))
+	}
+
+	// Gradle 模块缓存和 Ivy 缓存的布局与 Maven 仓库不同，只运行各自的检测器
+	switch config.Tree.Type {
+	case types.RepositoryGradle:
+		return []Detector{NewGradleDetector(config.TempMinAge)}, nil
+	case types.RepositoryIvy:
+		return []Detector{NewIvyDetector()}, nil
+	}
+
+	if config.KeepSnapshots > 0 {
+		detectors = append(detectors, NewSnapshotRetentionDetector(config.KeepSnapshots))
+	}
+	if config.KeepReleases > 0 {
+		detectors = append(detectors, NewReleasePruneDetector(config.KeepReleases))
+	}
+	if config.UnusedFor > 0 {
+		detectors = append(detectors, NewUnusedDetector(config.UnusedFor))
+	}
+	if config.MaxSize > 0 {
+		// 大小预算需要在其他检测器的结果之上计算，放在最后
+		detectors = append(detectors, NewBudgetDetector(config.MaxSize))
+	}
+	return detectors, nil
+}
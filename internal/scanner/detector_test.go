@@ -0,0 +1,203 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// suffixDetector 测试用检测器，标记文件名带指定后缀的文件所在目录
+type suffixDetector struct {
+	suffix string
+}
+
+func (sd *suffixDetector) Name() string { return "suffix" }
+
+func (sd *suffixDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && strings.HasSuffix(d.Name(), sd.suffix)
+}
+
+func (sd *suffixDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	dir := filepath.Dir(path)
+	return []types.Result{{Path: dir, Scope: scopeOf(dir)}}, nil
+}
+
+func TestLastUpdatedDetectorMatch(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "foo-1.0.jar.lastUpdated"), []byte("test"), 0644)
+	os.WriteFile(filepath.Join(dir, "foo-1.0.jar"), []byte("test"), 0644)
+	os.Mkdir(filepath.Join(dir, "bar.lastUpdated"), 0755)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{
+		"foo-1.0.jar.lastUpdated": true,
+		"foo-1.0.jar":             false,
+		"bar.lastUpdated":         false,
+	}
+
+	detector := NewLastUpdatedDetector()
+	for _, entry := range entries {
+		got := detector.Match(filepath.Join(dir, entry.Name()), entry)
+		if got != want[entry.Name()] {
+			t.Errorf("Match(%s) = %v, want %v", entry.Name(), got, want[entry.Name()])
+		}
+	}
+}
+
+func TestScanRepositoryDetectors(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	dir := t.TempDir()
+	failed := filepath.Join(dir, "org", "example", "failed", "1.0")
+	broken := filepath.Join(dir, "org", "example", "broken", "1.0")
+	writeFile(t, filepath.Join(failed, "failed-1.0.jar.lastUpdated"), "test")
+	writeFile(t, filepath.Join(broken, "broken-1.0.broken"), "test")
+
+	s := NewScanner(logger, NewLastUpdatedDetector(), &suffixDetector{suffix: ".broken"})
+	result := s.ScanRepository(types.ScanConfig{InputPath: dir, MaxConcurrentGoRoutines: 2})
+
+	want := map[string]string{
+		failed: "lastupdated",
+		broken: "suffix",
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("ScanRepository() found %d directories, want %d", len(result.Results), len(want))
+	}
+	for _, res := range result.Results {
+		if res.Detector != want[res.Path] {
+			t.Errorf("Result %s Detector = %q, want %q", res.Path, res.Detector, want[res.Path])
+		}
+	}
+}
+
+func TestLastUpdatedDetectorFailures(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "foo-1.0.jar.lastUpdated")
+	content := "#NOTE: This is a Maven Resolver internal implementation file, its format can be changed without prior notice.\n" +
+		"#Mon Jan 01 12:00:00 UTC 2024\n" +
+		"https\\://repo.maven.apache.org/maven2/.lastUpdated=1704110400000\n" +
+		"https\\://repo.maven.apache.org/maven2/.error=Could not transfer artifact foo\\:foo\\:jar\\:1.0\n" +
+		"@default-mirror-https\\://mirror.example.com/maven/.lastUpdated=1704110400000\n"
+	os.WriteFile(marker, []byte(content), 0644)
+
+	entries, _ := os.ReadDir(dir)
+	results, err := NewLastUpdatedDetector().Detect(dir, marker, entries[0])
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Path != dir {
+		t.Fatalf("Detect() = %v, want one result for %s", results, dir)
+	}
+
+	failures := results[0].Failures
+	if len(failures) != 2 {
+		t.Fatalf("Detect() found %d failures, want 2", len(failures))
+	}
+	if failures[0].Repository != "https://mirror.example.com/maven/" {
+		t.Errorf("Failures[0].Repository = %q", failures[0].Repository)
+	}
+	if failures[1].Repository != "https://repo.maven.apache.org/maven2/" {
+		t.Errorf("Failures[1].Repository = %q", failures[1].Repository)
+	}
+	if failures[1].Error != "Could not transfer artifact foo:foo:jar:1.0" {
+		t.Errorf("Failures[1].Error = %q", failures[1].Error)
+	}
+	if failures[1].LastAttempt.UnixMilli() != 1704110400000 {
+		t.Errorf("Failures[1].LastAttempt = %v", failures[1].LastAttempt)
+	}
+}
+
+func TestLastUpdatedDetectorResolverStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantFiles int
+	}{
+		{
+			name:    "no failures",
+			content: "maven-metadata-central.xml/@default-central-https\\://repo.maven.apache.org/maven2/.lastUpdated=1704110400000\n",
+		},
+		{
+			name: "cached metadata failure",
+			content: "maven-metadata-central.xml/@default-central-https\\://repo.maven.apache.org/maven2/.lastUpdated=1704110400000\n" +
+				"maven-metadata-central.xml/@default-central-https\\://repo.maven.apache.org/maven2/.error=\n",
+			wantFiles: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "org", "example", "foo")
+			status := filepath.Join(dir, ResolverStatusFile)
+			writeFile(t, status, tt.content)
+			writeFile(t, filepath.Join(dir, "maven-metadata-central.xml"), "<metadata/>")
+			writeFile(t, filepath.Join(dir, "1.0", "foo-1.0.jar"), "PK")
+
+			entries, _ := os.ReadDir(dir)
+			var entry fs.DirEntry
+			for _, e := range entries {
+				if e.Name() == ResolverStatusFile {
+					entry = e
+				}
+			}
+
+			detector := NewLastUpdatedDetector()
+			if !detector.Match(status, entry) {
+				t.Fatalf("Match(%s) = false, want true", status)
+			}
+			results, err := detector.Detect(dir, status, entry)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if tt.wantFiles == 0 {
+				if len(results) != 0 {
+					t.Errorf("Detect() = %v, want no results", results)
+				}
+				return
+			}
+			if len(results) != 1 {
+				t.Fatalf("Detect() returned %d results, want 1", len(results))
+			}
+			if len(results[0].Files) != tt.wantFiles || len(results[0].Markers) != 1 {
+				t.Errorf("Detect() Files = %v, Markers = %v", results[0].Files, results[0].Markers)
+			}
+			if results[0].Scope != types.ScopeArtifact {
+				t.Errorf("Detect() Scope = %v, want artifact", results[0].Scope)
+			}
+		})
+	}
+}
+
+func TestNewDetectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    int
+		wantErr bool
+	}{
+		{"none", nil, 0, false},
+		{"builtin", []string{"lastupdated", "corrupt-jar"}, 2, false},
+		{"unknown", []string{"lastupdated", "nope"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDetectors(tt.names, types.ScanConfig{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDetectors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("NewDetectors() returned %d detectors, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
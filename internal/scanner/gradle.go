@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// GradleDetectorName Gradle 模块缓存检测器名称
+const GradleDetectorName = "gradle"
+
+// GradleDetector 校验 Gradle 模块缓存（files-2.1）中每个文件的 SHA-1 是否与其所在的目录名一致
+//
+// 缓存按 "<group>/<module>/<version>/<sha1>/<文件>" 存放文件，未完成的下载和损坏的 jar 的摘要与目录名不一致；
+// 没有任何文件且修改时间早于 minAge 的 SHA-1 目录同样会被标记，避免误删并发构建刚开始的下载。
+// 结果只删除对应的 SHA-1 目录，Gradle 下次构建时会重新下载。
+type GradleDetector struct {
+	minAge time.Duration
+	now    func() time.Time
+}
+
+// NewGradleDetector 创建 Gradle 模块缓存检测器
+func NewGradleDetector(minAge time.Duration) *GradleDetector {
+	return &GradleDetector{
+		minAge: minAge,
+		now:    time.Now,
+	}
+}
+
+// Name 返回检测器名称
+func (gd *GradleDetector) Name() string {
+	return GradleDetectorName
+}
+
+// Match 判断是否为以 SHA-1 命名的目录或其中的文件
+func (gd *GradleDetector) Match(path string, d fs.DirEntry) bool {
+	if d.IsDir() {
+		return isSHA1Dir(d.Name())
+	}
+	return isSHA1Dir(filepath.Base(filepath.Dir(path)))
+}
+
+// Detect 标记摘要与目录名不一致的文件所在的 SHA-1 目录，以及空的 SHA-1 目录
+func (gd *GradleDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	if d.IsDir() {
+		if depthOf(root, path) != 4 {
+			return nil, nil
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				return nil, nil
+			}
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil, err
+		}
+		if gd.now().Sub(info.ModTime()) < gd.minAge {
+			return nil, nil
+		}
+		return []types.Result{{
+			Path:   path,
+			Scope:  types.ScopeFile,
+			Reason: "empty SHA-1 directory left by an interrupted download",
+		}}, nil
+	}
+
+	if depthOf(root, path) != 5 {
+		return nil, nil
+	}
+	digests, err := computeDigests(path, map[string]string{"sha1": ""})
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	expected, actual := filepath.Base(dir), digests["sha1"]
+	// Gradle 的目录名省略了摘要开头的 0
+	if strings.TrimLeft(expected, "0") == strings.TrimLeft(actual, "0") {
+		return nil, nil
+	}
+	return []types.Result{{
+		Path:   dir,
+		Scope:  types.ScopeFile,
+		Reason: fmt.Sprintf("SHA-1 of %s does not match its directory", d.Name()),
+		Mismatches: []types.ChecksumMismatch{{
+			File:      path,
+			Algorithm: "sha1",
+			Expected:  expected,
+			Actual:    actual,
+		}},
+	}}, nil
+}
+
+// isSHA1Dir 判断目录名是否为 Gradle 使用的十六进制 SHA-1 摘要
+func isSHA1Dir(name string) bool {
+	if len(name) == 0 || len(name) > 40 {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// gradleScopeOf 按相对于 Gradle 模块缓存根目录的深度判断目录所在的层级
+func gradleScopeOf(root, dir string) types.Scope {
+	switch depthOf(root, dir) {
+	case 1:
+		return types.ScopeGroup
+	case 2:
+		return types.ScopeArtifact
+	case 3:
+		return types.ScopeVersion
+	default:
+		return types.ScopeFile
+	}
+}
+
+// depthOf 返回路径相对于根目录的层数，根目录本身为 0，不在根目录下时返回 -1
+func depthOf(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return -1
+	}
+	if rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// treeScopeOf 判断目录在扫描的目录树中所在的层级，无法按坐标划分的目录返回空字符串
+func treeScopeOf(config types.ScanConfig, dir string) types.Scope {
+	switch config.Tree.Type {
+	case types.RepositoryGradle:
+		return gradleScopeOf(config.InputPath, dir)
+	case types.RepositoryIvy:
+		return ivyScopeOf(config.InputPath, dir)
+	}
+	return scopeOf(dir)
+}
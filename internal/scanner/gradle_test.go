@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// gradleFile 按 Gradle 模块缓存的布局写入文件，返回其所在的 SHA-1 目录
+func gradleFile(t *testing.T, root, module, name, content string) string {
+	t.Helper()
+	sum := sha1.Sum([]byte(content))
+	dir := filepath.Join(root, module, strings.TrimLeft(hex.EncodeToString(sum[:]), "0"))
+	writeFile(t, filepath.Join(dir, name), content)
+	return dir
+}
+
+func TestGradleDetector(t *testing.T) {
+	root := t.TempDir()
+	module := filepath.Join("org.example", "foo", "1.0")
+
+	gradleFile(t, root, module, "foo-1.0.pom", "<project/>")
+	// 摘要以 0 开头的文件，Gradle 的目录名省略开头的 0
+	for i := 0; ; i++ {
+		content := fmt.Sprintf("jar %d", i)
+		if sum := sha1.Sum([]byte(content)); sum[0] < 0x10 {
+			gradleFile(t, root, module, "foo-1.0.jar", content)
+			break
+		}
+	}
+	truncated := gradleFile(t, root, module, "foo-1.0-sources.jar", "complete sources")
+	writeFile(t, filepath.Join(truncated, "foo-1.0-sources.jar"), "compl")
+	empty := filepath.Join(root, module, "da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	// 刚创建的空目录可能是并发构建正在进行的下载
+	fresh := filepath.Join(root, module, "a9993e364706816aba3e25717850c26c9cd0d89d")
+	for _, dir := range []string{empty, fresh} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(empty, old, old)
+
+	config := scanConfig(root)
+	config.TempMinAge = time.Hour
+	config.Tree = types.Tree{Path: root, Type: types.RepositoryGradle}
+	detectors, err := NewDetectors([]string{"lastupdated", "corrupt-jar"}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := NewScanner(logger.NewCustomLogger(), detectors...).ScanRepository(config)
+
+	if len(result.Results) != 2 {
+		t.Fatalf("ScanRepository() found %d results, want 2: %+v", len(result.Results), result.Results)
+	}
+	want := map[string]types.Coordinates{
+		truncated: {GroupID: "org.example", ArtifactID: "foo", Version: "1.0", Classifier: "sources", Extension: "jar"},
+		empty:     {GroupID: "org.example", ArtifactID: "foo", Version: "1.0"},
+	}
+	for _, res := range result.Results {
+		coordinates, ok := want[res.Path]
+		if !ok {
+			t.Errorf("unexpected result %s", res.Path)
+			continue
+		}
+		if res.Scope != types.ScopeFile || res.Detector != GradleDetectorName {
+			t.Errorf("result %s Scope = %s, Detector = %s, want file, %s", res.Path, res.Scope, res.Detector, GradleDetectorName)
+		}
+		if res.Coordinates != coordinates {
+			t.Errorf("result %s Coordinates = %+v, want %+v", res.Path, res.Coordinates, coordinates)
+		}
+		if res.Path == truncated && len(res.Mismatches) != 1 {
+			t.Errorf("truncated file Mismatches = %+v, want one sha1 mismatch", res.Mismatches)
+		}
+	}
+}
+
+func TestGradleScopeOf(t *testing.T) {
+	root := filepath.Join("cache", "files-2.1")
+	tests := []struct {
+		rel  string
+		want types.Scope
+	}{
+		{"org.example", types.ScopeGroup},
+		{filepath.Join("org.example", "foo"), types.ScopeArtifact},
+		{filepath.Join("org.example", "foo", "1.0"), types.ScopeVersion},
+		{filepath.Join("org.example", "foo", "1.0", "0a1b"), types.ScopeFile},
+	}
+	for _, tt := range tests {
+		if got := gradleScopeOf(root, filepath.Join(root, tt.rel)); got != tt.want {
+			t.Errorf("gradleScopeOf(%s) = %s, want %s", tt.rel, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/resolver"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// CascadeDetectorName 级联删除的依赖方结果使用的检测器名称
+const CascadeDetectorName = "cascade"
+
+// brokenDetectors 报告损坏或下载不完整构件的检测器，只有它们的结果需要影响分析
+var brokenDetectors = []string{"lastupdated", "corrupt-jar", "checksum", "content", "pom", "incomplete"}
+
+// isBroken 判断结果是否由报告损坏构件的检测器标记
+func isBroken(res types.Result) bool {
+	for _, name := range strings.Split(res.Detector, ",") {
+		if slices.Contains(brokenDetectors, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeImpact 为损坏的版本目录补充直接和间接依赖它的本地构件
+//
+// 反向依赖索引覆盖整个本地仓库，分离布局中包括其他子树中的依赖方；通常由调用方按仓库构建一次后通过 SetReverseIndex 传入。
+// 启用级联删除时，这些依赖方的版本目录也会被计划删除，以便与损坏的构件一起重新解析。
+func (s *Scanner) analyzeImpact(config types.ScanConfig, result *types.ScanResult) {
+	index := s.index
+	if index == nil {
+		var err error
+		index, err = resolver.NewResolver(repositoryOf(config)).ReverseIndex()
+		if err != nil {
+			s.logger.Warning("Failed to build the reverse dependency index: %v (impact analysis skipped)", err)
+			return
+		}
+	}
+
+	wholeDirs := make(map[string]int)
+	for i, res := range result.Results {
+		if len(res.Files) == 0 {
+			wholeDirs[res.Path] = i
+		}
+	}
+
+	var cascaded []types.Result
+	cascadedPaths := make(map[string]bool)
+	for i := range result.Results {
+		res := &result.Results[i]
+		if res.Scope != types.ScopeVersion || !isBroken(*res) {
+			continue
+		}
+		groupID, artifactID, version, ok := versionCoordinates(config.InputPath, res.Path)
+		if !ok {
+			continue
+		}
+		res.Dependents, res.TransitiveDependents = index.Impact(groupID, artifactID, version)
+		res.UnversionedDependents = index.Unversioned(groupID, artifactID)
+		if !config.Cascade {
+			continue
+		}
+
+		broken := groupID + ":" + artifactID + ":" + version
+		for _, dependent := range append(slices.Clone(res.Dependents), res.TransitiveDependents...) {
+			// 分离布局中只级联删除当前子树中的依赖方，其他子树（如本地安装的构件）不受影响
+			dir := versionDirOf(config.InputPath, dependent)
+			if _, ok := wholeDirs[dir]; ok || cascadedPaths[dir] {
+				continue
+			}
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			if _, ok := scheduledAncestor(wholeDirs, dir); ok {
+				continue
+			}
+			dependentResult := types.Result{
+				Root:     config.InputPath,
+				Path:     dir,
+				Detector: CascadeDetectorName,
+				Scope:    types.ScopeVersion,
+				Reason:   "depends on " + broken,
+			}
+			if !s.filter.AllowsResult(dependentResult) {
+				continue
+			}
+			cascadedPaths[dir] = true
+			cascaded = append(cascaded, dependentResult)
+		}
+	}
+	if len(cascaded) == 0 {
+		return
+	}
+
+	// 已有文件级结果的目录合并为整目录删除，需要重新计算大小
+	merged := resolveOverlaps(mergeResults(append(result.Results, cascaded...)))
+	var remeasure []types.Result
+	for _, res := range merged {
+		if cascadedPaths[res.Path] {
+			remeasure = append(remeasure, res)
+		}
+	}
+	sizes := make(map[string]int64)
+	for _, res := range s.measureResults(remeasure, config.MaxConcurrentGoRoutines) {
+		sizes[res.Path] = res.Size
+	}
+
+	results := merged[:0]
+	for _, res := range merged {
+		if cascadedPaths[res.Path] {
+			size, ok := sizes[res.Path]
+			if !ok {
+				continue
+			}
+			res.Size = size
+		}
+		results = append(results, res)
+	}
+
+	result.Results = results
+	result.TotalSize = 0
+	for _, res := range results {
+		result.TotalSize += res.Size
+	}
+	if result.RepositorySize > 0 {
+		result.ProjectedSize = result.RepositorySize - result.TotalSize
+	}
+}
+
+// versionDirOf 返回 "groupId:artifactId:version" 对应的版本目录
+func versionDirOf(root, key string) string {
+	parts := strings.SplitN(key, ":", 3)
+	segments := append([]string{root}, strings.Split(parts[0], ".")...)
+	return filepath.Join(append(segments, parts[1], parts[2])...)
+}
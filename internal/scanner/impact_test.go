@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/internal/resolver"
+)
+
+// writeDependentPOM 在版本目录中写入依赖另一个构件的 POM
+func writeDependentPOM(t *testing.T, root, artifactID, dependsOn, scope string) {
+	t.Helper()
+	deps := ""
+	if dependsOn != "" {
+		deps = "<dependencies><dependency><groupId>org.example</groupId><artifactId>" + dependsOn +
+			"</artifactId><version>1.0</version><scope>" + scope + "</scope></dependency></dependencies>"
+	}
+	writeFile(t, filepath.Join(root, "org", "example", artifactID, "1.0", artifactID+"-1.0.pom"),
+		"<project><groupId>org.example</groupId><artifactId>"+artifactID+"</artifactId><version>1.0</version>"+deps+"</project>")
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	tests := []struct {
+		name      string
+		cascade   bool
+		wantPaths []string
+	}{
+		{"report only", false, []string{"core"}},
+		{"cascade", true, []string{"app", "core", "mid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeDependentPOM(t, root, "core", "", "")
+			writeFile(t, filepath.Join(root, "org", "example", "core", "1.0", "core-1.0.jar"), "<html>Login required</html>")
+			writeDependentPOM(t, root, "mid", "core", "compile")
+			writeDependentPOM(t, root, "app", "mid", "runtime")
+			writeDependentPOM(t, root, "tests", "core", "test")
+
+			config := scanConfig(root)
+			config.Impact = true
+			config.Cascade = tt.cascade
+			s := NewScanner(logger.NewCustomLogger(), NewCorruptArchiveDetector())
+			result := s.ScanRepository(config)
+
+			var paths []string
+			for _, res := range result.Results {
+				paths = append(paths, filepath.Base(filepath.Dir(res.Path)))
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Fatalf("result paths = %v, want %v", paths, tt.wantPaths)
+			}
+
+			for _, res := range result.Results {
+				if filepath.Base(filepath.Dir(res.Path)) != "core" {
+					if res.Detector != CascadeDetectorName || res.Reason != "depends on org.example:core:1.0" || res.Size == 0 {
+						t.Errorf("cascaded result = %+v", res)
+					}
+					continue
+				}
+				if want := []string{"org.example:mid:1.0"}; !slices.Equal(res.Dependents, want) {
+					t.Errorf("Dependents = %v, want %v", res.Dependents, want)
+				}
+				if want := []string{"org.example:app:1.0"}; !slices.Equal(res.TransitiveDependents, want) {
+					t.Errorf("TransitiveDependents = %v, want %v", res.TransitiveDependents, want)
+				}
+			}
+
+			var total int64
+			for _, res := range result.Results {
+				total += res.Size
+			}
+			if result.TotalSize != total {
+				t.Errorf("TotalSize = %d, want %d", result.TotalSize, total)
+			}
+		})
+	}
+}
+
+func TestAnalyzeImpactSharedIndex(t *testing.T) {
+	root := t.TempDir()
+	writeDependentPOM(t, root, "core", "", "")
+	writeFile(t, filepath.Join(root, "org", "example", "core", "1.0", "core-1.0.jar"), "<html>Login required</html>")
+	writeDependentPOM(t, root, "mid", "core", "compile")
+
+	index, err := resolver.NewResolver(root).ReverseIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 索引构建之后出现的依赖方不在索引中，说明扫描器使用了传入的索引而没有重新构建
+	writeDependentPOM(t, root, "late", "core", "compile")
+
+	config := scanConfig(root)
+	config.Impact = true
+	s := NewScanner(logger.NewCustomLogger(), NewCorruptArchiveDetector())
+	s.SetReverseIndex(index)
+	result := s.ScanRepository(config)
+
+	if len(result.Results) != 1 {
+		t.Fatalf("ScanRepository() found %d results, want 1", len(result.Results))
+	}
+	if want := []string{"org.example:mid:1.0"}; !slices.Equal(result.Results[0].Dependents, want) {
+		t.Errorf("Dependents = %v, want %v", result.Results[0].Dependents, want)
+	}
+}
+
+func TestAnalyzeImpactUnversioned(t *testing.T) {
+	root := t.TempDir()
+	writeDependentPOM(t, root, "core", "", "")
+	writeFile(t, filepath.Join(root, "org", "example", "core", "1.0", "core-1.0.jar"), "<html>Login required</html>")
+	writeFile(t, filepath.Join(root, "org", "example", "loose", "1.0", "loose-1.0.pom"),
+		"<project><groupId>org.example</groupId><artifactId>loose</artifactId><version>1.0</version>"+
+			"<dependencies><dependency><groupId>org.example</groupId><artifactId>core</artifactId></dependency></dependencies></project>")
+
+	config := scanConfig(root)
+	config.Cascade = true
+	s := NewScanner(logger.NewCustomLogger(), NewCorruptArchiveDetector())
+	result := s.ScanRepository(config)
+
+	// 没有版本的依赖只报告，不级联删除
+	if len(result.Results) != 1 {
+		t.Fatalf("ScanRepository() found %d results, want only the broken artifact", len(result.Results))
+	}
+	res := result.Results[0]
+	if len(res.Dependents) != 0 {
+		t.Errorf("Dependents = %v, want none", res.Dependents)
+	}
+	if want := []string{"org.example:loose:1.0"}; !slices.Equal(res.UnversionedDependents, want) {
+		t.Errorf("UnversionedDependents = %v, want %v", res.UnversionedDependents, want)
+	}
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// IncompleteDetector 查找只有 POM、缺少其打包类型对应主构件的版本目录
+//
+// 例如 foo-1.0.pom 声明 <packaging>jar</packaging>，目录中却没有 foo-1.0.jar。
+// 无法解析的 POM 和未知的打包类型会被忽略，前者由 PomDetector 负责。
+type IncompleteDetector struct{}
+
+// NewIncompleteDetector 创建不完整构件检测器
+func NewIncompleteDetector() *IncompleteDetector {
+	return &IncompleteDetector{}
+}
+
+// Name 返回检测器名称
+func (id *IncompleteDetector) Name() string {
+	return "incomplete"
+}
+
+// Match 判断是否为版本目录中的 POM 文件
+func (id *IncompleteDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && strings.HasSuffix(d.Name(), ".pom") && isArtifactFile(path)
+}
+
+// Detect 读取 POM 的打包类型，检查对应的主构件是否存在
+func (id *IncompleteDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	project, err := pom.ParseFile(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	packaging := project.EffectivePackaging()
+	ext, ok := pom.ArtifactExtension(packaging)
+	if !ok || ext == "" {
+		return nil, nil
+	}
+
+	main := strings.TrimSuffix(path, ".pom") + "." + ext
+	if _, err := os.Stat(main); err == nil {
+		return nil, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	return []types.Result{{
+		Path:   dir,
+		Scope:  scopeOf(dir),
+		Reason: fmt.Sprintf("incomplete: %s is missing (packaging %s)", filepath.Base(main), packaging),
+	}}, nil
+}
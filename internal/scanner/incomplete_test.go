@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestIncompleteDetector(t *testing.T) {
+	root := t.TempDir()
+	artifact := filepath.Join(root, "org", "example", "foo")
+	pomWith := func(packaging string) string {
+		return "<project><groupId>org.example</groupId><artifactId>foo</artifactId><version>1.0</version>" + packaging + "</project>"
+	}
+
+	// 完整的 jar 构件
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.pom"), pomWith(""))
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.jar"), "PK")
+	// 默认 jar 打包但缺少 jar
+	writeFile(t, filepath.Join(artifact, "1.1", "foo-1.1.pom"), pomWith(""))
+	// war 打包但缺少 war
+	writeFile(t, filepath.Join(artifact, "1.2", "foo-1.2.pom"), pomWith("<packaging>war</packaging>"))
+	writeFile(t, filepath.Join(artifact, "1.2", "foo-1.2.jar"), "PK")
+	// pom 打包没有主构件
+	writeFile(t, filepath.Join(artifact, "1.3", "foo-1.3.pom"), pomWith("<packaging>pom</packaging>"))
+	// 未知打包类型
+	writeFile(t, filepath.Join(artifact, "1.4", "foo-1.4.pom"), pomWith("<packaging>nbm</packaging>"))
+	// 带时间戳的快照
+	writeFile(t, filepath.Join(artifact, "1.5-SNAPSHOT", "foo-1.5-20260101.120000-1.pom"), pomWith(""))
+	writeFile(t, filepath.Join(artifact, "1.5-SNAPSHOT", "foo-1.5-20260101.120000-1.jar"), "PK")
+
+	s := NewScanner(logger.NewCustomLogger(), NewIncompleteDetector())
+	result := s.ScanRepository(scanConfig(root))
+
+	want := map[string]string{
+		filepath.Join(artifact, "1.1"): "foo-1.1.jar",
+		filepath.Join(artifact, "1.2"): "foo-1.2.war",
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("ScanRepository() found %d results, want %d: %v", len(result.Results), len(want), result.Results)
+	}
+	for _, res := range result.Results {
+		if !strings.HasPrefix(res.Reason, "incomplete") || !strings.Contains(res.Reason, want[res.Path]) {
+			t.Errorf("result %s Reason = %q, want incomplete reason naming %s", res.Path, res.Reason, want[res.Path])
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// IvyDetectorName Ivy 缓存检测器名称
+const IvyDetectorName = "ivy"
+
+const (
+	// ivyDataPrefix、ivyDataSuffix Ivy 在模块目录中为每个版本记录构件来源和解析状态的 ivydata-<revision>.properties
+	ivyDataPrefix = "ivydata-"
+	ivyDataSuffix = ".properties"
+	// ivyArtifactKeyPrefix ivydata 中构件记录的键前缀，完整形式为 "artifact:<名称>#<类型>#<扩展名>#<哈希>.<字段>"
+	ivyArtifactKeyPrefix = "artifact:"
+)
+
+// IvyDetector 检查 Ivy 缓存（~/.ivy2/cache）中每个模块版本的 ivydata-<revision>.properties
+//
+// ivydata 中构件记录的 exists=false 表示缓存了一次解析失败，与 Maven 的 .lastUpdated 类似；
+// 记录的构件在缓存中缺失、为空或是损坏的归档时同样标记该版本。同一模块所有版本的文件都在模块目录中，
+// 结果只删除该版本的 ivydata、模块描述文件和构件，Ivy 下次解析时会重新下载。
+type IvyDetector struct{}
+
+// NewIvyDetector 创建 Ivy 缓存检测器
+func NewIvyDetector() *IvyDetector {
+	return &IvyDetector{}
+}
+
+// Name 返回检测器名称
+func (id *IvyDetector) Name() string {
+	return IvyDetectorName
+}
+
+// Match 判断是否为 ivydata 文件
+func (id *IvyDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && ivyRevision(d.Name()) != ""
+}
+
+// Detect 解析 ivydata 中缓存的解析失败，并检查记录的构件是否完好
+func (id *IvyDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	if depthOf(root, path) < 3 {
+		return nil, nil
+	}
+	props, err := parsePropertiesFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	revision := ivyRevision(d.Name())
+	revisions := ivyRevisions(dir)
+	files := append([]string{path}, existingFiles(
+		filepath.Join(dir, "ivy-"+revision+".xml"),
+		filepath.Join(dir, "ivy-"+revision+".xml.original"),
+	)...)
+
+	var problems []string
+	var failures []types.Failure
+	for _, artifact := range parseIvyArtifacts(props) {
+		if !artifact.exists {
+			repository := artifact.location
+			if repository == "" {
+				repository = props["resolver"]
+			}
+			failures = append(failures, types.Failure{
+				Repository:  repository,
+				Error:       fmt.Sprintf("%s not found", artifact),
+				LastAttempt: artifact.lastChecked,
+			})
+			continue
+		}
+		if strings.HasSuffix(artifact.typ, ".original") {
+			// 转换前的 POM 等原始描述文件，已随 ivy-<revision>.xml.original 列出
+			continue
+		}
+
+		found := artifact.files(dir, revision, revisions)
+		if len(found) == 0 {
+			problems = append(problems, fmt.Sprintf("missing %s", artifact))
+			continue
+		}
+		for _, file := range found {
+			files = appendUnique(files, file)
+			if problem := checkIvyFile(file); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	if len(problems) == 0 && len(failures) == 0 {
+		return nil, nil
+	}
+
+	result := types.Result{
+		Path:     dir,
+		Scope:    types.ScopeArtifact,
+		Files:    files,
+		Failures: failures,
+	}
+	if len(failures) > 0 {
+		// ivydata 本身就是失败标记，只删除标记模式下删除它即可让 Ivy 重新解析
+		result.Markers = []string{path}
+		problems = append([]string{fmt.Sprintf("%d artifacts of %s cached as not found", len(failures), revision)}, problems...)
+	}
+	result.Reason = strings.Join(problems, "; ")
+	return []types.Result{result}, nil
+}
+
+// ivyArtifact ivydata 中记录的一个构件
+type ivyArtifact struct {
+	name        string
+	typ         string
+	ext         string
+	location    string // 下载构件的解析器或仓库
+	exists      bool
+	lastChecked time.Time
+}
+
+// String 返回 "<名称>.<扩展名> (<类型>)" 形式的构件描述
+func (a ivyArtifact) String() string {
+	return fmt.Sprintf("%s.%s (%s)", a.name, a.ext, a.typ)
+}
+
+// files 按 Ivy 默认的缓存布局查找构件文件："<类型>s/<名称>-<revision>[-<分类器>].<扩展名>"，模块描述文件直接位于模块目录中
+//
+// 分类器与更长的版本号无法区分，例如 foo-1.0-M1.jar，这种文件归属于模块目录中存在的更长版本。
+func (a ivyArtifact) files(dir, revision string, revisions []string) []string {
+	typeDir := filepath.Join(dir, a.typ+"s")
+	if a.typ == "ivy" {
+		typeDir = dir
+	}
+	entries, err := os.ReadDir(typeDir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !a.matches(entry.Name(), revision) {
+			continue
+		}
+		owned := true
+		for _, other := range revisions {
+			if len(other) > len(revision) && a.matches(entry.Name(), other) {
+				owned = false
+				break
+			}
+		}
+		if owned {
+			files = append(files, filepath.Join(typeDir, entry.Name()))
+		}
+	}
+	return files
+}
+
+// matches 判断文件名是否为该构件在指定版本下的文件
+func (a ivyArtifact) matches(name, revision string) bool {
+	rest, ok := strings.CutPrefix(name, a.name+"-"+revision)
+	if !ok {
+		return false
+	}
+	if rest == "."+a.ext {
+		return true
+	}
+	classifier, ok := strings.CutSuffix(rest, "."+a.ext)
+	return ok && len(classifier) > 1 && classifier[0] == '-'
+}
+
+// parseIvyArtifacts 按键前缀汇总 ivydata 中的构件记录，按前缀排序
+func parseIvyArtifacts(props map[string]string) []ivyArtifact {
+	byPrefix := make(map[string]*ivyArtifact)
+	for key, value := range props {
+		if !strings.HasPrefix(key, ivyArtifactKeyPrefix) {
+			continue
+		}
+		// 哈希中没有点，最后一个点之后是字段名；类型中可能有点，如 pom.original
+		dot := strings.LastIndex(key, ".")
+		if dot < 0 {
+			continue
+		}
+		prefix, field := key[:dot], key[dot+1:]
+		parts := strings.Split(strings.TrimPrefix(prefix, ivyArtifactKeyPrefix), "#")
+		if len(parts) != 4 {
+			continue
+		}
+
+		artifact, ok := byPrefix[prefix]
+		if !ok {
+			artifact = &ivyArtifact{name: parts[0], typ: parts[1], ext: parts[2], exists: true}
+			byPrefix[prefix] = artifact
+		}
+		switch field {
+		case "location":
+			artifact.location = value
+		case "exists":
+			artifact.exists = value != "false"
+		case "lastchecked":
+			if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+				artifact.lastChecked = time.UnixMilli(millis)
+			}
+		}
+	}
+
+	prefixes := make([]string, 0, len(byPrefix))
+	for prefix := range byPrefix {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	artifacts := make([]ivyArtifact, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		artifacts = append(artifacts, *byPrefix[prefix])
+	}
+	return artifacts
+}
+
+// checkIvyFile 检查构件文件是否为空或是损坏的归档，返回问题描述
+func checkIvyFile(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Sprintf("unreadable %s: %v", filepath.Base(path), err)
+	}
+	if info.Size() == 0 {
+		return fmt.Sprintf("empty %s", filepath.Base(path))
+	}
+	if IsArchive(path) {
+		if err := VerifyArchive(path); err != nil {
+			return fmt.Sprintf("corrupt archive %s: %v", filepath.Base(path), err)
+		}
+	}
+	return ""
+}
+
+// ivyRevision 返回 ivydata-<revision>.properties 文件名中的版本，不是 ivydata 文件时返回空字符串
+func ivyRevision(name string) string {
+	if !strings.HasPrefix(name, ivyDataPrefix) || !strings.HasSuffix(name, ivyDataSuffix) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(name, ivyDataPrefix), ivyDataSuffix)
+}
+
+// ivyRevisions 返回模块目录中有 ivydata 文件的所有版本
+func ivyRevisions(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var revisions []string
+	for _, entry := range entries {
+		if revision := ivyRevision(entry.Name()); revision != "" && !entry.IsDir() {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// ivyScopeOf 按相对于 Ivy 缓存根目录的深度判断目录所在的层级，模块目录以下的目录返回空字符串
+func ivyScopeOf(root, dir string) types.Scope {
+	switch depthOf(root, dir) {
+	case 1:
+		return types.ScopeGroup
+	case 2:
+		return types.ScopeArtifact
+	default:
+		return ""
+	}
+}
+
+// resultIvyRevision 返回 Ivy 结果所涉及的唯一版本，结果合并了多个版本时返回空字符串
+func resultIvyRevision(res types.Result) string {
+	var revision string
+	for _, file := range res.Files {
+		if r := ivyRevision(filepath.Base(file)); r != "" {
+			if revision != "" && revision != r {
+				return ""
+			}
+			revision = r
+		}
+	}
+	return revision
+}
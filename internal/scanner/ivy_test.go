@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// ivyData 返回记录了指定构件的 ivydata 内容，每个构件形如 "<名称>#<类型>#<扩展名>"
+func ivyData(artifacts ...string) string {
+	var b strings.Builder
+	b.WriteString("#ivy cached data file\nresolver=central\n")
+	for i, artifact := range artifacts {
+		key := "artifact\\:" + strings.ReplaceAll(artifact, "#", "\\#") + "\\#" + string(rune('1'+i))
+		b.WriteString(key + ".location=central\n")
+		b.WriteString(key + ".is-local=false\n")
+	}
+	return b.String()
+}
+
+func TestIvyDetector(t *testing.T) {
+	root := t.TempDir()
+	archive := string(buildArchive(t, "A.class", "class"))
+
+	good := filepath.Join(root, "org.example", "good")
+	writeFile(t, filepath.Join(good, "ivydata-1.0.properties"), ivyData("ivy#ivy#xml", "good#jar#jar"))
+	writeFile(t, filepath.Join(good, "ivy-1.0.xml"), "<ivy-module/>")
+	writeFile(t, filepath.Join(good, "jars", "good-1.0.jar"), archive)
+
+	failed := filepath.Join(root, "org.example", "failed")
+	failedData := filepath.Join(failed, "ivydata-2.0.properties")
+	writeFile(t, failedData, ivyData("ivy#ivy#xml")+
+		"artifact\\:failed\\#jar\\#jar\\#9.exists=false\n"+
+		"artifact\\:failed\\#jar\\#jar\\#9.lastchecked=1704110400000\n")
+	writeFile(t, filepath.Join(failed, "ivy-2.0.xml"), "<ivy-module/>")
+
+	// 1.0 的 jar 损坏，1.0-M1 的 jar 完好但文件名看起来像 1.0 带分类器的 jar
+	broken := filepath.Join(root, "org.example", "broken")
+	writeFile(t, filepath.Join(broken, "ivydata-1.0.properties"), ivyData("broken#jar#jar", "broken#source#jar"))
+	writeFile(t, filepath.Join(broken, "jars", "broken-1.0.jar"), "not a zip")
+	writeFile(t, filepath.Join(broken, "ivydata-1.0-M1.properties"), ivyData("broken#jar#jar"))
+	writeFile(t, filepath.Join(broken, "jars", "broken-1.0-M1.jar"), archive)
+
+	config := scanConfig(root)
+	config.Tree = types.Tree{Path: root, Type: types.RepositoryIvy}
+	detectors, err := NewDetectors([]string{"lastupdated"}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := NewScanner(logger.NewCustomLogger(), detectors...).ScanRepository(config)
+
+	if len(result.Results) != 2 {
+		t.Fatalf("ScanRepository() found %d results, want 2: %+v", len(result.Results), result.Results)
+	}
+	brokenResult, failedResult := result.Results[0], result.Results[1]
+
+	wantFiles := []string{filepath.Join(broken, "ivydata-1.0.properties"), filepath.Join(broken, "jars", "broken-1.0.jar")}
+	if brokenResult.Path != broken || !slices.Equal(brokenResult.Files, wantFiles) {
+		t.Errorf("broken result = %s %v, want %s %v", brokenResult.Path, brokenResult.Files, broken, wantFiles)
+	}
+	for _, problem := range []string{"corrupt archive broken-1.0.jar", "missing broken.jar (source)"} {
+		if !strings.Contains(brokenResult.Reason, problem) {
+			t.Errorf("broken result Reason = %q, want it to mention %q", brokenResult.Reason, problem)
+		}
+	}
+	if want := (types.Coordinates{GroupID: "org.example", ArtifactID: "broken", Version: "1.0"}); brokenResult.Coordinates != want {
+		t.Errorf("broken result Coordinates = %+v, want %+v", brokenResult.Coordinates, want)
+	}
+
+	if failedResult.Path != failed || !slices.Equal(failedResult.Markers, []string{failedData}) {
+		t.Errorf("failed result = %s, Markers %v, want %s with marker %s", failedResult.Path, failedResult.Markers, failed, failedData)
+	}
+	if len(failedResult.Failures) != 1 || failedResult.Failures[0].Repository != "central" ||
+		!failedResult.Failures[0].LastAttempt.Equal(time.UnixMilli(1704110400000)) {
+		t.Errorf("failed result Failures = %+v, want one cached failure from central", failedResult.Failures)
+	}
+	if len(failedResult.Files) != 2 {
+		t.Errorf("failed result Files = %v, want ivydata and ivy.xml", failedResult.Files)
+	}
+}
+
+func TestParseIvyArtifacts(t *testing.T) {
+	props := map[string]string{
+		"resolver":                                 "central",
+		"artifact:foo#jar#jar#-12.location":        "central",
+		"artifact:foo#jar#jar#-12.exists":          "false",
+		"artifact:ivy#pom.original#pom#7.is-local": "false",
+		"artifact:bad#key.location":                "central",
+	}
+	got := parseIvyArtifacts(props)
+	want := []ivyArtifact{
+		{name: "foo", typ: "jar", ext: "jar", location: "central", exists: false},
+		{name: "ivy", typ: "pom.original", ext: "pom", exists: true},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseIvyArtifacts() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// scopeOf 判断目录位于 groupId、artifactId 还是版本层级
+//
+// 版本目录中的文件以 "<artifactId>-<version>" 开头；artifactId 目录的子目录是版本目录；
+// 其余带子目录的目录视为 groupId 目录。没有子目录的叶子目录按版本目录处理。
+func scopeOf(dir string) types.Scope {
+	if isVersionDir(dir) {
+		return types.ScopeVersion
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return types.ScopeVersion
+	}
+
+	hasSubdirs := false
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		hasSubdirs = true
+		if isVersionDir(filepath.Join(dir, entry.Name())) {
+			return types.ScopeArtifact
+		}
+	}
+
+	if !hasSubdirs {
+		return types.ScopeVersion
+	}
+	return types.ScopeGroup
+}
+
+// isVersionDir 判断目录是否为 "<artifactId>/<version>" 结构的版本目录
+func isVersionDir(dir string) bool {
+	version := filepath.Base(dir)
+	artifactID := filepath.Base(filepath.Dir(dir))
+
+	prefix := artifactID + "-" + version
+	if base, ok := strings.CutSuffix(version, "-SNAPSHOT"); ok {
+		// 带时间戳的快照文件形如 foo-1.0-20260101.120000-1.jar
+		prefix = artifactID + "-" + base + "-"
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionCoordinates 根据 "<groupId 路径>/<artifactId>/<version>" 目录结构推断版本目录的坐标
+func versionCoordinates(root, dir string) (groupID, artifactID, version string, ok bool) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", "", "", false
+	}
+	c, err := types.ParseCoordinates(rel, types.ScopeVersion)
+	if err != nil {
+		return "", "", "", false
+	}
+	return c.GroupID, c.ArtifactID, c.Version, true
+}
+
+// isMetadataFile 判断文件是否为 maven-metadata 相关文件
+func isMetadataFile(name string) bool {
+	return strings.HasPrefix(name, "maven-metadata")
+}
+
+// existingFiles 返回列表中实际存在的文件
+func existingFiles(paths ...string) []string {
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// checksumExtensions Maven 校验文件扩展名，按优先级从强到弱排列
+var checksumExtensions = []string{".sha512", ".sha256", ".sha1", ".md5"}
+
+// isChecksumFile 判断文件是否为校验文件或签名文件
+func isChecksumFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".asc" || slices.Contains(checksumExtensions, ext)
+}
+
+// isArtifactFile 判断版本目录中的文件是否为构件本身，而非校验文件、标记文件或元数据
+func isArtifactFile(path string) bool {
+	name := filepath.Base(path)
+	if isChecksumFile(name) || isMetadataFile(name) ||
+		strings.HasSuffix(name, ".lastUpdated") ||
+		name == ResolverStatusFile || name == "_remote.repositories" {
+		return false
+	}
+
+	// 构件文件名以 "<artifactId>-" 开头，artifactId 是版本目录的上级目录名
+	artifactID := filepath.Base(filepath.Dir(filepath.Dir(path)))
+	return strings.HasPrefix(name, artifactID+"-")
+}
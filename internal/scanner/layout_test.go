@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// writeFile 创建文件及其所在目录
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// scanConfig 返回测试用的扫描配置
+func scanConfig(root string) types.ScanConfig {
+	return types.ScanConfig{InputPath: root, MaxConcurrentGoRoutines: 2}
+}
+
+func TestScopeOf(t *testing.T) {
+	root := t.TempDir()
+	group := filepath.Join(root, "org", "example")
+	artifact := filepath.Join(group, "foo")
+	version := filepath.Join(artifact, "1.0")
+	snapshot := filepath.Join(artifact, "1.1-SNAPSHOT")
+
+	writeFile(t, filepath.Join(artifact, "maven-metadata-central.xml"), "<metadata/>")
+	writeFile(t, filepath.Join(version, "foo-1.0.pom"), "<project/>")
+	writeFile(t, filepath.Join(snapshot, "foo-1.1-20260101.120000-1.jar"), "PK")
+	writeFile(t, filepath.Join(root, "leaf", "file.lastUpdated"), "")
+
+	tests := []struct {
+		name string
+		dir  string
+		want types.Scope
+	}{
+		{"group", group, types.ScopeGroup},
+		{"artifact", artifact, types.ScopeArtifact},
+		{"version", version, types.ScopeVersion},
+		{"snapshot version", snapshot, types.ScopeVersion},
+		{"leaf directory", filepath.Join(root, "leaf"), types.ScopeVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scopeOf(tt.dir); got != tt.want {
+				t.Errorf("scopeOf(%s) = %v, want %v", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOverlaps(t *testing.T) {
+	results := []types.Result{
+		{Path: "/repo/org", Files: []string{"/repo/org/maven-metadata-central.xml.lastUpdated"}},
+		{Path: "/repo/org/foo"},
+		{Path: "/repo/org/foo-bar/1.0"},
+		{Path: "/repo/org/foo/1.0"},
+		{Path: "/repo/org/foo/1.1", Files: []string{"/repo/org/foo/1.1/foo-1.1.jar.lastUpdated"},
+			Markers: []string{"/repo/org/foo/1.1/foo-1.1.jar.lastUpdated"}},
+	}
+
+	got := resolveOverlaps(results)
+	want := []string{"/repo/org", "/repo/org/foo", "/repo/org/foo-bar/1.0"}
+	if len(got) != len(want) {
+		t.Fatalf("resolveOverlaps() returned %d results, want %d: %v", len(got), len(want), got)
+	}
+	for i, res := range got {
+		if res.Path != want[i] {
+			t.Errorf("resolveOverlaps()[%d] = %s, want %s", i, res.Path, want[i])
+		}
+	}
+	if len(got[1].Markers) != 1 {
+		t.Errorf("resolveOverlaps() should move nested markers to the ancestor, got %v", got[1].Markers)
+	}
+}
@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// PomDetector 查找无法解析、根元素不是 <project> 或坐标与目录不符的 POM 文件
+type PomDetector struct{}
+
+// NewPomDetector 创建 POM 检测器
+func NewPomDetector() *PomDetector {
+	return &PomDetector{}
+}
+
+// Name 返回检测器名称
+func (pd *PomDetector) Name() string {
+	return "pom"
+}
+
+// Match 判断是否为 POM 文件
+func (pd *PomDetector) Match(path string, d fs.DirEntry) bool {
+	return !d.IsDir() && strings.HasSuffix(d.Name(), ".pom")
+}
+
+// Detect 解析 POM 并校验其 groupId/artifactId/version 与所在目录一致
+func (pd *PomDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	reason := ""
+	project, err := pom.ParseFile(path)
+	if err != nil {
+		reason = fmt.Sprintf("malformed POM %s: %v", d.Name(), err)
+	} else if mismatch := checkCoordinates(root, path, project); mismatch != "" {
+		reason = fmt.Sprintf("POM %s does not match its directory: %s", d.Name(), mismatch)
+	}
+	if reason == "" {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(path)
+	result := types.Result{Path: dir, Scope: scopeOf(dir), Reason: reason}
+	if result.Scope != types.ScopeVersion {
+		result.Files = []string{path}
+	}
+	return []types.Result{result}, nil
+}
+
+// checkCoordinates 比对 POM 坐标与 "<groupId 路径>/<artifactId>/<version>" 目录结构，返回不一致的描述
+func checkCoordinates(root, path string, project *pom.Project) string {
+	groupID, artifactID, version, ok := versionCoordinates(root, filepath.Dir(path))
+	if !ok {
+		// 不在标准仓库布局中，无法从路径推断坐标
+		return ""
+	}
+
+	want := map[string]string{
+		"groupId":    groupID,
+		"artifactId": artifactID,
+		"version":    version,
+	}
+	got := map[string]string{
+		"groupId":    project.EffectiveGroupID(),
+		"artifactId": strings.TrimSpace(project.ArtifactID),
+		"version":    project.EffectiveVersion(),
+	}
+
+	var mismatches []string
+	for _, field := range []string{"groupId", "artifactId", "version"} {
+		switch {
+		case got[field] == "":
+			mismatches = append(mismatches, field+" is missing")
+		case strings.Contains(got[field], "${"):
+			// 未展开的属性（如 ${revision}）无法在不构建模型的情况下比对
+		case got[field] != want[field]:
+			mismatches = append(mismatches, fmt.Sprintf("%s is %q, directory says %q", field, got[field], want[field]))
+		}
+	}
+	return strings.Join(mismatches, ", ")
+}
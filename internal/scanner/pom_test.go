@@ -0,0 +1,40 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestPomDetector(t *testing.T) {
+	root := t.TempDir()
+	artifact := filepath.Join(root, "org", "example", "foo")
+	pomFor := func(groupID, version string) string {
+		return "<project><groupId>" + groupID + "</groupId><artifactId>foo</artifactId><version>" + version + "</version></project>"
+	}
+
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.pom"), pomFor("org.example", "1.0"))
+	writeFile(t, filepath.Join(artifact, "1.1", "foo-1.1.pom"), "<project><groupId>org.example</groupId><artifact")
+	writeFile(t, filepath.Join(artifact, "1.2", "foo-1.2.pom"), "<html><body>Not Found</body></html>")
+	writeFile(t, filepath.Join(artifact, "1.3", "foo-1.3.pom"), pomFor("com.other", "1.3"))
+	writeFile(t, filepath.Join(artifact, "1.4", "foo-1.4.pom"), pomFor("org.example", "${revision}"))
+
+	s := NewScanner(logger.NewCustomLogger(), NewPomDetector())
+	result := s.ScanRepository(scanConfig(root))
+
+	want := map[string]string{
+		filepath.Join(artifact, "1.1"): "malformed",
+		filepath.Join(artifact, "1.2"): "expected <project>",
+		filepath.Join(artifact, "1.3"): "groupId",
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("ScanRepository() found %d results, want %d: %v", len(result.Results), len(want), result.Results)
+	}
+	for _, res := range result.Results {
+		if !strings.Contains(res.Reason, want[res.Path]) {
+			t.Errorf("result %s Reason = %q, want it to mention %q", res.Path, res.Reason, want[res.Path])
+		}
+	}
+}
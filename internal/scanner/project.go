@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"io/fs"
+
+	"github.com/lyj404/clean-mvn/internal/resolver"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// ProjectDetectorName 项目闭包检测器名称
+const ProjectDetectorName = "project"
+
+// ProjectDetector 标记不属于项目依赖闭包的版本目录，用于把共享的本地仓库精简为只服务于指定项目的缓存
+type ProjectDetector struct {
+	closure *resolver.Closure
+}
+
+// NewProjectDetector 创建项目闭包检测器
+func NewProjectDetector(closure *resolver.Closure) *ProjectDetector {
+	return &ProjectDetector{closure: closure}
+}
+
+// Name 返回检测器名称
+func (pd *ProjectDetector) Name() string {
+	return ProjectDetectorName
+}
+
+// Match 所有目录都可能是版本目录，具体判断在 Detect 中进行
+func (pd *ProjectDetector) Match(path string, d fs.DirEntry) bool {
+	return d.IsDir()
+}
+
+// Detect 版本目录的坐标不在闭包中时整体计划删除
+func (pd *ProjectDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	if !isVersionDir(path) {
+		return nil, nil
+	}
+	groupID, artifactID, version, ok := versionCoordinates(root, path)
+	if !ok || pd.closure.Contains(groupID, artifactID, version) {
+		return nil, nil
+	}
+	return []types.Result{{
+		Path:   path,
+		Scope:  types.ScopeVersion,
+		Reason: "not in the dependency closure of the given projects",
+	}}, nil
+}
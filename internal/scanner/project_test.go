@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/internal/resolver"
+)
+
+func TestProjectDetector(t *testing.T) {
+	root := t.TempDir()
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, "pom.xml"), `<project>
+  <groupId>com.acme</groupId><artifactId>app</artifactId><version>1.0</version><packaging>pom</packaging>
+  <dependencies><dependency><groupId>org.example</groupId><artifactId>foo</artifactId><version>1.0</version></dependency></dependencies>
+  <build><pluginManagement><plugins><plugin><artifactId>maven-install-plugin</artifactId><version>3.1.1</version></plugin></plugins></pluginManagement></build>
+</project>`)
+
+	used := filepath.Join(root, "org", "example", "foo", "1.0")
+	plugin := filepath.Join(root, "org", "apache", "maven", "plugins", "maven-install-plugin", "3.1.1")
+	unused := filepath.Join(root, "org", "example", "foo", "0.9")
+	other := filepath.Join(root, "org", "other", "bar", "2.0")
+	writeFile(t, filepath.Join(used, "foo-1.0.pom"), "<project/>")
+	writeFile(t, filepath.Join(used, "foo-1.0.jar"), "jar")
+	writeFile(t, filepath.Join(plugin, "maven-install-plugin-3.1.1.pom"), "<project/>")
+	writeFile(t, filepath.Join(unused, "foo-0.9.jar"), "jar")
+	writeFile(t, filepath.Join(other, "bar-2.0.jar"), "jar")
+
+	closure, err := resolver.NewResolver(root).Resolve([]string{project})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	s := NewScanner(logger.NewCustomLogger(), NewProjectDetector(closure))
+	result := s.ScanRepository(scanConfig(root))
+
+	var found []string
+	for _, res := range result.Results {
+		found = append(found, res.Path)
+		if len(res.Files) != 0 {
+			t.Errorf("result %s Files = %v, want whole-directory result", res.Path, res.Files)
+		}
+	}
+	want := []string{unused, other}
+	if len(found) != len(want) || found[0] != want[0] || found[1] != want[1] {
+		t.Errorf("ScanRepository() found %v, want %v", found, want)
+	}
+}
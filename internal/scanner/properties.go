@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+)
+
+// parseProperties 解析 Java properties 格式的内容（Maven 的 .lastUpdated 等文件使用此格式）
+func parseProperties(r io.Reader) (map[string]string, error) {
+	lines, err := readPropertyLines(r)
+	props := make(map[string]string)
+	for _, line := range lines {
+		if line.entry {
+			props[line.key] = line.value
+		}
+	}
+	return props, err
+}
+
+// propertyLine properties 文件中的一个逻辑行，保留原始文本以便改写文件时不改变其他行
+type propertyLine struct {
+	raw   string // 原始文本，续行包含换行符
+	entry bool   // 是否为键值对，空行和注释为 false
+	key   string
+	value string
+}
+
+// readPropertyLines 按逻辑行读取 properties 内容，续行合并为一个逻辑行
+func readPropertyLines(r io.Reader) ([]propertyLine, error) {
+	var lines []propertyLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var logical, raw strings.Builder
+	flush := func() {
+		key, value := splitProperty(logical.String())
+		lines = append(lines, propertyLine{raw: raw.String(), entry: true, key: unescapeProperty(key), value: unescapeProperty(value)})
+		logical.Reset()
+		raw.Reset()
+	}
+	for scanner.Scan() {
+		text := scanner.Text()
+		line := strings.TrimLeft(text, " \t\f")
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			lines = append(lines, propertyLine{raw: text})
+			continue
+		}
+		if raw.Len() > 0 {
+			raw.WriteByte('\n')
+		}
+		raw.WriteString(text)
+
+		// 以奇数个反斜杠结尾表示续行
+		if trailingBackslashes(line)%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+		flush()
+	}
+	if logical.Len() > 0 {
+		flush()
+	}
+
+	return lines, scanner.Err()
+}
+
+// parsePropertiesFile 读取并解析 properties 文件
+func parsePropertiesFile(path string) (map[string]string, error) {
+	file, err := atime.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseProperties(file)
+}
+
+// splitProperty 在第一个未转义的 '='、':' 或空白处拆分键和值
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = rest[1:]
+			}
+			return line[:i], strings.TrimLeft(rest, " \t\f")
+		}
+	}
+	return line, ""
+}
+
+// unescapeProperty 处理 properties 中的转义序列
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// trailingBackslashes 统计行尾连续反斜杠的数量
+func trailingBackslashes(line string) int {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "comments and blank lines",
+			input: "#NOTE: comment\n! another\n\nkey=value\n",
+			want:  map[string]string{"key": "value"},
+		},
+		{
+			name:  "escaped colon in key",
+			input: "https\\://repo.maven.apache.org/maven2/.lastUpdated=1700000000000\n",
+			want:  map[string]string{"https://repo.maven.apache.org/maven2/.lastUpdated": "1700000000000"},
+		},
+		{
+			name:  "colon and whitespace separators",
+			input: "a:1\nb 2\nc = 3\n",
+			want:  map[string]string{"a": "1", "b": "2", "c": "3"},
+		},
+		{
+			name:  "line continuation",
+			input: "key=first \\\n    second\n",
+			want:  map[string]string{"key": "first second"},
+		},
+		{
+			name:  "unicode escape",
+			input: "key=caf\\u00e9\n",
+			want:  map[string]string{"key": "café"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProperties(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("parseProperties() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("parseProperties() = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("parseProperties()[%q] = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
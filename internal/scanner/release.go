@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// ReleasePruneDetector 对每个 groupId:artifactId 按 Maven 版本语义保留最新的 N 个正式版本目录
+//
+// 快照版本目录不参与排序也不会被删除，其余较旧的版本目录整体计划删除。
+type ReleasePruneDetector struct {
+	keep int
+}
+
+// NewReleasePruneDetector 创建旧版本清理检测器，keep 为每个构件保留的版本数
+func NewReleasePruneDetector(keep int) *ReleasePruneDetector {
+	return &ReleasePruneDetector{keep: keep}
+}
+
+// Name 返回检测器名称
+func (rd *ReleasePruneDetector) Name() string {
+	return "release-prune"
+}
+
+// Match 所有目录都可能是 artifactId 目录，具体判断在 Detect 中进行
+func (rd *ReleasePruneDetector) Match(path string, d fs.DirEntry) bool {
+	return d.IsDir()
+}
+
+// Detect 列出 artifactId 目录下的正式版本，标记超出保留数量的旧版本
+func (rd *ReleasePruneDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*pom.ComparableVersion
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasSuffix(entry.Name(), "-SNAPSHOT") {
+			continue
+		}
+		if isVersionDir(filepath.Join(path, entry.Name())) {
+			versions = append(versions, pom.ParseVersion(entry.Name()))
+		}
+	}
+	if len(versions) <= rd.keep {
+		return nil, nil
+	}
+
+	// 从新到旧排序
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) > 0
+	})
+
+	kept := make([]string, 0, rd.keep)
+	for _, version := range versions[:rd.keep] {
+		kept = append(kept, version.String())
+	}
+
+	results := make([]types.Result, 0, len(versions)-rd.keep)
+	for _, version := range versions[rd.keep:] {
+		results = append(results, types.Result{
+			Path:   filepath.Join(path, version.String()),
+			Scope:  types.ScopeVersion,
+			Reason: fmt.Sprintf("superseded by newer releases (keeping %s)", strings.Join(kept, ", ")),
+		})
+	}
+	return results, nil
+}
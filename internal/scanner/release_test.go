@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestReleasePruneDetector(t *testing.T) {
+	root := t.TempDir()
+	artifact := filepath.Join(root, "org", "example", "foo")
+	for _, version := range []string{"1.9", "1.10", "1.10.1", "2.0-RC1", "2.0-alpha", "2.1-SNAPSHOT"} {
+		writeFile(t, filepath.Join(artifact, version, "foo-"+version+".pom"), "<project/>")
+	}
+	// 只有一个版本的构件不受影响
+	writeFile(t, filepath.Join(root, "org", "example", "bar", "1.0", "bar-1.0.pom"), "<project/>")
+
+	s := NewScanner(logger.NewCustomLogger(), NewReleasePruneDetector(2))
+	result := s.ScanRepository(scanConfig(root))
+
+	want := map[string]bool{
+		filepath.Join(artifact, "1.9"):    true,
+		filepath.Join(artifact, "1.10"):   true,
+		filepath.Join(artifact, "1.10.1"): true,
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("ScanRepository() found %d results, want %d: %v", len(result.Results), len(want), result.Results)
+	}
+	for _, res := range result.Results {
+		if !want[res.Path] {
+			t.Errorf("unexpected prune candidate %s", res.Path)
+		}
+	}
+}
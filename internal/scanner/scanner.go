@@ -1,49 +1,310 @@
 package scanner
 
 import (
+	"errors"
+	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/lyj404/clean-mvn/internal/filter"
 	"github.com/lyj404/clean-mvn/internal/logger"
 	"github.com/lyj404/clean-mvn/internal/progress"
+	"github.com/lyj404/clean-mvn/internal/resolver"
 	"github.com/lyj404/clean-mvn/pkg/types"
 )
 
 // Scanner Maven 仓库扫描器
 type Scanner struct {
-	logger *logger.CustomLogger
+	logger    *logger.CustomLogger
+	detectors []Detector
+	filter    *filter.Filter
+	index     *resolver.ReverseIndex
 }
 
-// NewScanner 创建新的扫描器
-func NewScanner(logger *logger.CustomLogger) *Scanner {
+// NewScanner 创建新的扫描器，未指定检测器时默认使用 .lastUpdated 检测器
+func NewScanner(logger *logger.CustomLogger, detectors ...Detector) *Scanner {
+	if len(detectors) == 0 {
+		detectors = []Detector{NewLastUpdatedDetector()}
+	}
 	return &Scanner{
-		logger: logger,
+		logger:    logger,
+		detectors: detectors,
 	}
 }
 
-// ScanRepository 扫描 Maven 仓库，查找包含 .lastUpdated 文件的目录
+// SetFilter 设置坐标过滤器，遍历时跳过不被允许的目录，并丢弃会删除不被允许内容的结果
+func (s *Scanner) SetFilter(f *filter.Filter) {
+	s.filter = f
+}
+
+// SetReverseIndex 设置影响分析使用的反向依赖索引，同一仓库的各子树共用一个索引，未设置时在影响分析中构建
+func (s *Scanner) SetReverseIndex(index *resolver.ReverseIndex) {
+	s.index = index
+}
+
+// ScanRepository 扫描 Maven 仓库，对每个文件和目录运行检测器，返回需要清理的目录
 func (s *Scanner) ScanRepository(config types.ScanConfig) types.ScanResult {
 	startTime := time.Now()
 
+	scanProgressCount := atomic.Int64{}
+	scanStop := make(chan bool)
+	scanDone := make(chan bool)
+
+	go s.runProgressBar(&scanProgressCount, scanStop, scanDone)
+
+	scan := s.collect(config, &scanProgressCount)
+
+	scanStop <- true
+	<-scanDone
+
+	scanResult := s.finish(config, scan)
+	scanResult.Duration = time.Since(startTime).Milliseconds()
+	return scanResult
+}
+
+// ScanRoots 并发扫描多个仓库根目录，合并为一个扫描结果，scanners 和 configs 一一对应
+//
+// 每个根目录使用独立的扫描器，保留策略等检测器状态按根目录分别计算；
+// 大小预算按 RepositoryRoot 所属的仓库计算一次，分离布局中的所有子树共用同一个预算。
+func ScanRoots(scanners []*Scanner, configs []types.ScanConfig) types.ScanResult {
+	startTime := time.Now()
+	if len(scanners) == 0 {
+		return types.ScanResult{}
+	}
+
+	scanProgressCount := atomic.Int64{}
+	scanStop := make(chan bool)
+	scanDone := make(chan bool)
+
+	go scanners[0].runProgressBar(&scanProgressCount, scanStop, scanDone)
+
+	scans := make([]rootScan, len(scanners))
+	var wg sync.WaitGroup
+	for i := range scanners {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			scans[i] = scanners[i].collect(configs[i], &scanProgressCount)
+		}(i)
+	}
+	wg.Wait()
+
+	scanStop <- true
+	<-scanDone
+
+	// 大小预算按仓库计算：分离布局中同一仓库的所有子树共用一个预算
+	results := make([]types.ScanResult, len(scanners))
+	for i, s := range scanners {
+		results[i] = s.summarize(configs[i], scans[i])
+		s.finalize(&results[i], false)
+	}
+	groups := groupByRepository(configs)
+	protected := make([]int64, len(groups))
+	for g, group := range groups {
+		if configs[group[0]].MaxSize > 0 {
+			protected[g] = protectedSize(scanners[group[0]], configs, group)
+		}
+		applyBudget(scanners, configs, results, group, protected[g])
+	}
+
+	combined := types.ScanResult{}
+	var errs []error
+	for i, s := range scanners {
+		s.complete(configs[i], &results[i])
+		result := results[i]
+		combined.Results = append(combined.Results, result.Results...)
+		combined.TotalSize += result.TotalSize
+		combined.MissingChecksums = append(combined.MissingChecksums, result.MissingChecksums...)
+		combined.Roots = append(combined.Roots, types.RootSummary{
+			Root:           configs[i].InputPath,
+			Tree:           configs[i].Tree,
+			Count:          len(result.Results),
+			TotalSize:      result.TotalSize,
+			RepositorySize: result.RepositorySize,
+			ProjectedSize:  result.ProjectedSize,
+			Error:          result.Error,
+		})
+		if result.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", configs[i].InputPath, result.Error))
+		}
+	}
+
+	// 汇总每个仓库的预算统计，未扫描的受保护子树也计入仓库大小
+	for g, group := range groups {
+		budget := configs[group[0]].MaxSize
+		if budget == 0 {
+			continue
+		}
+		summary := types.RootSummary{Root: repositoryOf(configs[group[0]]), RepositorySize: protected[g]}
+		for _, i := range group {
+			summary.Count += len(results[i].Results)
+			summary.TotalSize += results[i].TotalSize
+			summary.RepositorySize += results[i].RepositorySize
+		}
+		summary.ProjectedSize = summary.RepositorySize - summary.TotalSize
+		combined.Repositories = append(combined.Repositories, summary)
+		combined.RepositorySize += summary.RepositorySize
+		combined.ProjectedSize += summary.ProjectedSize
+		if combined.SizeBudget == 0 {
+			// Gradle 模块缓存不使用大小预算，取第一个设置了预算的仓库
+			combined.SizeBudget = budget
+		}
+	}
+	combined.Error = errors.Join(errs...)
+	combined.Duration = time.Since(startTime).Milliseconds()
+	return combined
+}
+
+// groupByRepository 按所属仓库的根目录对扫描配置分组，返回每组配置的下标，保持配置的顺序
+func groupByRepository(configs []types.ScanConfig) [][]int {
+	var groups [][]int
+	index := make(map[string]int)
+	for i, config := range configs {
+		repo := repositoryOf(config)
+		g, ok := index[repo]
+		if !ok {
+			g = len(groups)
+			index[repo] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// repositoryOf 返回扫描配置所属仓库的根目录
+func repositoryOf(config types.ScanConfig) string {
+	if config.RepositoryRoot != "" {
+		return config.RepositoryRoot
+	}
+	return config.InputPath
+}
+
+// applyBudget 对同一仓库的所有子树运行一次大小预算，再把结果分回各子树
+//
+// 仓库大小包括各子树的大小和未扫描的受保护子树的大小，淘汰顺序在所有子树的版本目录之间统一排序。
+func applyBudget(scanners []*Scanner, configs []types.ScanConfig, results []types.ScanResult, group []int, protected int64) {
+	var budget *BudgetDetector
+	for _, i := range group {
+		bd := scanners[i].budget()
+		if bd == nil {
+			continue
+		}
+		if budget == nil {
+			budget = bd
+		} else {
+			budget.adopt(bd)
+		}
+	}
+	if budget == nil {
+		return
+	}
+
+	merged := types.ScanResult{RepositorySize: protected}
+	for _, i := range group {
+		merged.Results = append(merged.Results, results[i].Results...)
+		merged.TotalSize += results[i].TotalSize
+		merged.RepositorySize += results[i].RepositorySize
+	}
+	budget.Finalize(&merged)
+
+	for _, i := range group {
+		tree := &results[i]
+		tree.Results, tree.TotalSize = nil, 0
+		for _, res := range merged.Results {
+			if depthOf(configs[i].InputPath, res.Path) > 0 {
+				tree.Results = append(tree.Results, res)
+				tree.TotalSize += res.Size
+			}
+		}
+		tree.SizeBudget = merged.SizeBudget
+		tree.ProjectedSize = tree.RepositorySize - tree.TotalSize
+	}
+}
+
+// protectedSize 统计一组扫描配置中受保护子树的总大小，同一子树只计算一次
+func protectedSize(s *Scanner, configs []types.ScanConfig, group []int) int64 {
+	var size int64
+	seen := make(map[string]bool)
+	for _, i := range group {
+		for _, tree := range configs[i].ProtectedTrees {
+			if seen[tree] {
+				continue
+			}
+			seen[tree] = true
+			if n, err := s.getDirSize(tree); err == nil {
+				size += n
+			}
+		}
+	}
+	return size
+}
+
+// filterResults 丢弃会删除坐标过滤器不允许的内容的结果，例如包含被排除版本的 artifactId 目录
+func (s *Scanner) filterResults(config types.ScanConfig, results []types.Result) []types.Result {
+	if s.filter == nil {
+		return results
+	}
+	allowed := results[:0]
+	for _, res := range results {
+		res.Root = config.InputPath
+		res.Coordinates, _ = resultCoordinates(config, res)
+		if !s.filter.AllowsResult(res) {
+			s.logger.Info("Skipping %s: it contains artifacts excluded by --include/--exclude.", res.Path)
+			continue
+		}
+		allowed = append(allowed, res)
+	}
+	return allowed
+}
+
+// allowsDir 判断遍历时是否需要进入目录，无法按坐标划分的目录（如 Ivy 模块目录下的 jars/）总是进入
+//
+// Maven 仓库的层级要读取目录内容才能确定，因此先把目录分别当作 groupId、artifactId 和版本目录判断，
+// 结论一致时直接返回，只有结论取决于层级时才读取目录内容。
+func (s *Scanner) allowsDir(config types.ScanConfig, dir string) bool {
+	if s.filter == nil {
+		return true
+	}
+	switch config.Tree.Type {
+	case types.RepositoryGradle, types.RepositoryIvy:
+		scope := treeScopeOf(config, dir)
+		return scope == "" || s.filter.AllowsDir(config.InputPath, dir, scope)
+	}
+
+	group := s.filter.AllowsDir(config.InputPath, dir, types.ScopeGroup)
+	if s.filter.AllowsDir(config.InputPath, dir, types.ScopeArtifact) == group &&
+		s.filter.AllowsDir(config.InputPath, dir, types.ScopeVersion) == group {
+		return group
+	}
+	return s.filter.AllowsDir(config.InputPath, dir, scopeOf(dir))
+}
+
+// rootScan 遍历一个仓库根目录得到的中间结果
+type rootScan struct {
+	results        []types.Result
+	repositorySize int64
+	err            error
+}
+
+// collect 遍历仓库，对每个文件和目录运行检测器，合并并计算结果的大小
+func (s *Scanner) collect(config types.ScanConfig, scanProgressCount *atomic.Int64) rootScan {
 	var (
 		mu                      sync.Mutex
-		results                 []types.Result
-		totalSizeAtomic         atomic.Int64
+		found                   []types.Result
+		repositorySize          int64
 		wg                      sync.WaitGroup
 		maxConcurrentGoRoutines = config.MaxConcurrentGoRoutines
 		sem                     = make(chan struct{}, maxConcurrentGoRoutines)
 	)
 
-	scanProgressCount := atomic.Int64{}
-	scanStop := make(chan bool)
-	scanDone := make(chan bool)
-
-	go s.runProgressBar(&scanProgressCount, scanStop, scanDone)
-
 	// 使用 filepath.WalkDir 遍历文件系统
 	err := filepath.WalkDir(config.InputPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -53,33 +314,47 @@ func (s *Scanner) ScanRepository(config types.ScanConfig) types.ScanResult {
 
 		scanProgressCount.Add(1) // 每次处理一个文件/目录，递增计数
 
-		if d.IsDir() {
-			return nil
+		// 尽早跳过坐标过滤器排除的子树，设置了大小预算时仍需计入仓库大小
+		if d.IsDir() && path != config.InputPath && !s.allowsDir(config, path) {
+			if config.MaxSize > 0 {
+				if size, err := s.getDirSize(path); err == nil {
+					repositorySize += size
+				}
+			}
+			return filepath.SkipDir
+		}
+
+		// 设置了大小预算时统计整个仓库的大小
+		if config.MaxSize > 0 && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				repositorySize += info.Size()
+			}
 		}
 
-		if strings.HasSuffix(d.Name(), ".lastUpdated") {
-			parentDir := filepath.Dir(path)
+		for _, detector := range s.detectors {
+			if !detector.Match(path, d) {
+				continue
+			}
 
 			sem <- struct{}{}
 			wg.Add(1)
-			go func(dirPath string) {
+			go func(detector Detector) {
 				defer wg.Done()
 				defer func() { <-sem }()
 
-				size, err := s.getDirSize(dirPath)
+				detected, err := detector.Detect(config.InputPath, path, d)
 				if err != nil {
-					s.logger.Warning("Error calculating directory %s size: %v (skipped)", dirPath, err)
+					s.logger.Warning("Detector %s failed on %s: %v (skipped)", detector.Name(), path, err)
 					return
 				}
 
 				mu.Lock()
-				result := types.Result{Path: dirPath, Size: size}
-				results = append(results, result)
+				for _, result := range detected {
+					result.Detector = detector.Name()
+					found = append(found, result)
+				}
 				mu.Unlock()
-				totalSizeAtomic.Add(size)
-			}(parentDir)
-
-			return filepath.SkipDir
+			}(detector)
 		}
 
 		return nil
@@ -87,30 +362,248 @@ func (s *Scanner) ScanRepository(config types.ScanConfig) types.ScanResult {
 
 	wg.Wait()
 
-	scanStop <- true
-	<-scanDone
+	// 在合并之前过滤，合并后的结果可能涉及 Ivy 模块目录中的多个版本
+	results := s.measureResults(resolveOverlaps(mergeResults(s.filterResults(config, found))), maxConcurrentGoRoutines)
+	return rootScan{results: results, repositorySize: repositorySize, err: err}
+}
 
-	endTime := time.Now()
-	duration := endTime.Sub(startTime).Milliseconds()
+// finish 统计总大小，运行检测器的收尾步骤和影响分析，并记录结果所在的根目录
+func (s *Scanner) finish(config types.ScanConfig, scan rootScan) types.ScanResult {
+	scanResult := s.summarize(config, scan)
+	s.finalize(&scanResult, true)
+	s.complete(config, &scanResult)
+	return scanResult
+}
 
-	uniqueResultsMap := make(map[string]types.Result)
-	for _, res := range results {
-		uniqueResultsMap[res.Path] = res
+// summarize 统计遍历结果的总大小，设置了大小预算时同时统计仓库大小
+func (s *Scanner) summarize(config types.ScanConfig, scan rootScan) types.ScanResult {
+	var totalSize int64
+	for _, res := range scan.results {
+		totalSize += res.Size
+	}
+
+	scanResult := types.ScanResult{
+		Results:   scan.results,
+		TotalSize: totalSize,
+		Error:     scan.err,
+	}
+	if config.MaxSize > 0 {
+		scanResult.RepositorySize = scan.repositorySize
+		scanResult.ProjectedSize = scan.repositorySize - totalSize
+	}
+	return scanResult
+}
+
+// finalize 运行检测器的收尾步骤，withBudget 为 false 时跳过大小预算，由调用方按仓库统一计算
+func (s *Scanner) finalize(scanResult *types.ScanResult, withBudget bool) {
+	for _, detector := range s.detectors {
+		if _, ok := detector.(*BudgetDetector); ok && !withBudget {
+			continue
+		}
+		if finalizer, ok := detector.(Finalizer); ok {
+			finalizer.Finalize(scanResult)
+		}
+	}
+}
+
+// complete 运行影响分析，并为结果填写所在的根目录和坐标
+func (s *Scanner) complete(config types.ScanConfig, scanResult *types.ScanResult) {
+	if config.Impact || config.Cascade {
+		s.analyzeImpact(config, scanResult)
+	}
+	s.assignCoordinates(config, scanResult)
+}
+
+// budget 返回扫描器的大小预算检测器，未设置预算时返回 nil
+func (s *Scanner) budget() *BudgetDetector {
+	for _, detector := range s.detectors {
+		if bd, ok := detector.(*BudgetDetector); ok {
+			return bd
+		}
+	}
+	return nil
+}
+
+// assignCoordinates 为每个结果填写所在的仓库根目录和坐标
+//
+// 无法按 "<groupId 路径>/<artifactId>/<version>" 解析的结果不一定是 Maven 构件，只给出警告，不会被删除；
+// 临时文件检测器标记的文件（如仓库根目录下 .locks 中的锁文件）本就不属于任何构件，不需要坐标。
+func (s *Scanner) assignCoordinates(config types.ScanConfig, scanResult *types.ScanResult) {
+	results := scanResult.Results[:0]
+	for _, res := range scanResult.Results {
+		res.Root = config.InputPath
+		coordinates, err := resultCoordinates(config, res)
+		if err != nil && res.Detector == TempDetectorName && len(res.Files) > 0 {
+			results = append(results, res)
+			continue
+		}
+		if err != nil {
+			s.logger.Warning("Skipping %s: not a valid groupId/artifactId/version layout: %v", res.Path, err)
+			scanResult.TotalSize -= res.Size
+			if config.MaxSize > 0 {
+				scanResult.ProjectedSize += res.Size
+			}
+			continue
+		}
+		res.Coordinates = coordinates
+		results = append(results, res)
+	}
+	scanResult.Results = results
+}
+
+// resultCoordinates 推断结果的坐标；结果只涉及单个构件文件时同时给出分类器和扩展名
+func resultCoordinates(config types.ScanConfig, res types.Result) (types.Coordinates, error) {
+	rel, err := filepath.Rel(res.Root, res.Path)
+	if err != nil {
+		return types.Coordinates{}, err
+	}
+	switch config.Tree.Type {
+	case types.RepositoryIvy:
+		return types.ParseIvyCoordinates(rel, res.Scope, resultIvyRevision(res))
+	case types.RepositoryGradle:
+		var file string
+		if res.Scope == types.ScopeFile {
+			if entries, err := os.ReadDir(res.Path); err == nil && len(entries) == 1 {
+				file = entries[0].Name()
+			}
+		}
+		return types.ParseGradleCoordinates(rel, res.Scope, file)
+	}
+	coordinates, err := types.ParseCoordinates(rel, res.Scope)
+	if err != nil {
+		return types.Coordinates{}, err
+	}
+	if res.Scope == types.ScopeVersion && len(res.Files) == 1 {
+		if rel, err := filepath.Rel(res.Root, res.Files[0]); err == nil {
+			if file, err := types.ParseArtifactPath(rel); err == nil {
+				coordinates = file
+			}
+		}
+	}
+	return coordinates, nil
+}
+
+// mergeResults 合并指向同一路径的检测结果，并按路径排序
+//
+// 同一目录既有整目录结果又有文件级结果时，以整目录结果为准。
+func mergeResults(found []types.Result) []types.Result {
+	uniqueResultsMap := make(map[string]*types.Result)
+	for _, res := range found {
+		existing, ok := uniqueResultsMap[res.Path]
+		if !ok {
+			res := res
+			uniqueResultsMap[res.Path] = &res
+			continue
+		}
+		if !slices.Contains(strings.Split(existing.Detector, ","), res.Detector) {
+			existing.Detector += "," + res.Detector
+		}
+		existing.Failures = append(existing.Failures, res.Failures...)
+		existing.Markers = appendUnique(existing.Markers, res.Markers...)
+		existing.Mismatches = append(existing.Mismatches, res.Mismatches...)
+		if existing.ContentType == "" {
+			existing.ContentType = res.ContentType
+		}
+		if res.LastUsed.After(existing.LastUsed) {
+			existing.LastUsed = res.LastUsed
+		}
+		existing.Dependents = appendUnique(existing.Dependents, res.Dependents...)
+		existing.TransitiveDependents = appendUnique(existing.TransitiveDependents, res.TransitiveDependents...)
+		existing.UnversionedDependents = appendUnique(existing.UnversionedDependents, res.UnversionedDependents...)
+		if res.Reason != "" && !strings.Contains(existing.Reason, res.Reason) {
+			existing.Reason = strings.TrimPrefix(existing.Reason+"; "+res.Reason, "; ")
+		}
+		if len(existing.Files) == 0 || len(res.Files) == 0 {
+			existing.Files = nil
+		} else {
+			existing.Files = appendUnique(existing.Files, res.Files...)
+		}
 	}
 
 	uniqueResults := make([]types.Result, 0, len(uniqueResultsMap))
-	var actualTotalSize int64
 	for _, res := range uniqueResultsMap {
-		uniqueResults = append(uniqueResults, res)
-		actualTotalSize += res.Size
+		uniqueResults = append(uniqueResults, *res)
 	}
+	sort.Slice(uniqueResults, func(i, j int) bool {
+		return uniqueResults[i].Path < uniqueResults[j].Path
+	})
+	return uniqueResults
+}
 
-	return types.ScanResult{
-		Results:   uniqueResults,
-		TotalSize: actualTotalSize,
-		Duration:  duration,
-		Error:     err,
+// resolveOverlaps 去除已被祖先目录结果覆盖的结果，保证父目录和子目录不会同时被计划删除
+//
+// 被去除结果的失败标记会并入祖先结果，以便只清理标记的模式仍能处理它们。
+// 调用前结果需按路径排序，祖先目录总是排在其子路径之前。
+func resolveOverlaps(results []types.Result) []types.Result {
+	wholeDirs := make(map[string]int)
+	resolved := make([]types.Result, 0, len(results))
+	for _, res := range results {
+		if ancestor, ok := scheduledAncestor(wholeDirs, res.Path); ok {
+			resolved[ancestor].Markers = appendUnique(resolved[ancestor].Markers, res.Markers...)
+			continue
+		}
+		if len(res.Files) == 0 {
+			wholeDirs[res.Path] = len(resolved)
+		}
+		resolved = append(resolved, res)
 	}
+	return resolved
+}
+
+// scheduledAncestor 查找已计划整体删除的上级目录，返回其在结果中的下标
+func scheduledAncestor(wholeDirs map[string]int, path string) (int, bool) {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if index, ok := wholeDirs[dir]; ok {
+			return index, true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return 0, false
+		}
+	}
+}
+
+// appendUnique 追加切片中尚不存在的元素
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// measureResults 并发计算每个结果的大小，计算失败的结果会被跳过
+func (s *Scanner) measureResults(results []types.Result, maxConcurrentGoRoutines int) []types.Result {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentGoRoutines)
+	failed := make([]bool, len(results))
+
+	for i := range results {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			size, err := s.getResultSize(results[i])
+			if err != nil {
+				s.logger.Warning("Error calculating directory %s size: %v (skipped)", results[i].Path, err)
+				failed[i] = true
+				return
+			}
+			results[i].Size = size
+		}(i)
+	}
+
+	wg.Wait()
+
+	measured := results[:0]
+	for i, res := range results {
+		if !failed[i] {
+			measured = append(measured, res)
+		}
+	}
+	return measured
 }
 
 // runProgressBar 运行扫描进度条
@@ -140,6 +633,26 @@ func (s *Scanner) runProgressBar(scanProgressCount *atomic.Int64, scanStop <-cha
 	}
 }
 
+// getResultSize 计算结果将要删除的大小：文件级结果只统计列出的文件
+func (s *Scanner) getResultSize(result types.Result) (int64, error) {
+	if len(result.Files) == 0 {
+		return s.getDirSize(result.Path)
+	}
+
+	var size int64
+	for _, file := range result.Files {
+		info, err := os.Stat(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return 0, err
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
+
 // getDirSize 递归计算目录的总大小
 func (s *Scanner) getDirSize(path string) (int64, error) {
 	var size int64
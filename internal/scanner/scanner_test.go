@@ -3,8 +3,11 @@ package scanner
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/lyj404/clean-mvn/internal/filter"
 	"github.com/lyj404/clean-mvn/internal/logger"
 	"github.com/lyj404/clean-mvn/pkg/types"
 )
@@ -39,10 +42,9 @@ func TestScanRepository(t *testing.T) {
 			name: "single lastUpdated file",
 			setup: func() string {
 				dir := t.TempDir()
-				subdir := filepath.Join(dir, "artifact")
-				os.Mkdir(subdir, 0755)
-				os.WriteFile(filepath.Join(subdir, "file.txt"), []byte("test"), 0644)
-				os.WriteFile(filepath.Join(subdir, "file.lastUpdated"), []byte("test"), 0644)
+				subdir := filepath.Join(dir, "org", "example", "artifact", "1.0")
+				writeFile(t, filepath.Join(subdir, "artifact-1.0.pom"), "test")
+				writeFile(t, filepath.Join(subdir, "artifact-1.0.jar.lastUpdated"), "test")
 				return dir
 			},
 			want: 1,
@@ -52,10 +54,10 @@ func TestScanRepository(t *testing.T) {
 			setup: func() string {
 				dir := t.TempDir()
 				for i := 0; i < 3; i++ {
-					subdir := filepath.Join(dir, "artifact"+string(rune('0'+i)))
-					os.Mkdir(subdir, 0755)
-					os.WriteFile(filepath.Join(subdir, "file.txt"), []byte("test"), 0644)
-					os.WriteFile(filepath.Join(subdir, "file.lastUpdated"), []byte("test"), 0644)
+					artifact := "artifact" + string(rune('0'+i))
+					subdir := filepath.Join(dir, "org", "example", artifact, "1.0")
+					writeFile(t, filepath.Join(subdir, artifact+"-1.0.pom"), "test")
+					writeFile(t, filepath.Join(subdir, artifact+"-1.0.jar.lastUpdated"), "test")
 				}
 				return dir
 			},
@@ -65,16 +67,24 @@ func TestScanRepository(t *testing.T) {
 			name: "nested directories",
 			setup: func() string {
 				dir := t.TempDir()
-				subdir1 := filepath.Join(dir, "level1")
-				os.Mkdir(subdir1, 0755)
-				subdir2 := filepath.Join(subdir1, "level2")
-				os.Mkdir(subdir2, 0755)
-				os.WriteFile(filepath.Join(subdir2, "file.txt"), []byte("test"), 0644)
-				os.WriteFile(filepath.Join(subdir2, "file.lastUpdated"), []byte("test"), 0644)
+				subdir := filepath.Join(dir, "org", "example", "level1", "level2", "artifact", "1.0")
+				writeFile(t, filepath.Join(subdir, "artifact-1.0.pom"), "test")
+				writeFile(t, filepath.Join(subdir, "artifact-1.0.jar.lastUpdated"), "test")
 				return dir
 			},
 			want: 1,
 		},
+		{
+			name: "not a groupId/artifactId/version layout",
+			setup: func() string {
+				dir := t.TempDir()
+				subdir := filepath.Join(dir, "artifact")
+				writeFile(t, filepath.Join(subdir, "file.txt"), "test")
+				writeFile(t, filepath.Join(subdir, "file.lastUpdated"), "test")
+				return dir
+			},
+			want: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -103,6 +113,50 @@ func TestScanRepository(t *testing.T) {
 	}
 }
 
+func TestScanRoots(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	var roots []string
+	for i := 1; i <= 2; i++ {
+		root := t.TempDir()
+		for j := 0; j < i; j++ {
+			artifact := "artifact" + string(rune('0'+j))
+			writeFile(t, filepath.Join(root, "org", "example", artifact, "1.0", artifact+"-1.0.jar.lastUpdated"), "test")
+		}
+		roots = append(roots, root)
+	}
+
+	var scanners []*Scanner
+	var configs []types.ScanConfig
+	for _, root := range roots {
+		scanners = append(scanners, NewScanner(logger))
+		configs = append(configs, types.ScanConfig{InputPath: root, MaxConcurrentGoRoutines: 2})
+	}
+	result := ScanRoots(scanners, configs)
+
+	if len(result.Results) != 3 {
+		t.Fatalf("ScanRoots() found %d results, want 3", len(result.Results))
+	}
+	if len(result.Roots) != 2 {
+		t.Fatalf("ScanRoots() Roots = %+v, want 2 roots", result.Roots)
+	}
+	var total int64
+	for i, summary := range result.Roots {
+		if summary.Root != roots[i] || summary.Count != i+1 {
+			t.Errorf("Roots[%d] = %+v, want root %s with %d results", i, summary, roots[i], i+1)
+		}
+		total += summary.TotalSize
+	}
+	if result.TotalSize != total {
+		t.Errorf("ScanRoots() TotalSize = %d, want sum of roots %d", result.TotalSize, total)
+	}
+	for _, res := range result.Results {
+		if rel, err := filepath.Rel(res.Root, res.Path); err != nil || strings.HasPrefix(rel, "..") {
+			t.Errorf("result %s has Root %s, want its own repository", res.Path, res.Root)
+		}
+	}
+}
+
 func TestGetDirSize(t *testing.T) {
 	logger := logger.NewCustomLogger()
 	s := NewScanner(logger)
@@ -166,3 +220,131 @@ func TestGetDirSize(t *testing.T) {
 		})
 	}
 }
+
+func TestScanRepositoryScopes(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	root := t.TempDir()
+	artifact := filepath.Join(root, "org", "example", "foo")
+	metadataMarker := filepath.Join(artifact, "maven-metadata-central.xml.lastUpdated")
+	writeFile(t, metadataMarker, "")
+	writeFile(t, filepath.Join(artifact, "maven-metadata-central.xml"), "<metadata/>")
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.jar"), "PK")
+	writeFile(t, filepath.Join(artifact, "1.0", "foo-1.0.pom"), "<project/>")
+	writeFile(t, filepath.Join(artifact, "1.1", "foo-1.1.pom"), "<project/>")
+	writeFile(t, filepath.Join(artifact, "1.1", "foo-1.1.jar.lastUpdated"), "")
+
+	s := NewScanner(logger)
+	result := s.ScanRepository(types.ScanConfig{InputPath: root, MaxConcurrentGoRoutines: 2})
+
+	if len(result.Results) != 2 {
+		t.Fatalf("ScanRepository() found %d results, want 2: %v", len(result.Results), result.Results)
+	}
+
+	metadata, version := result.Results[0], result.Results[1]
+	if metadata.Path != artifact || metadata.Scope != types.ScopeArtifact {
+		t.Errorf("metadata result = %s (%s), want %s (artifact)", metadata.Path, metadata.Scope, artifact)
+	}
+	if len(metadata.Files) != 2 || metadata.Files[0] != metadataMarker {
+		t.Errorf("metadata result Files = %v, want marker and stale metadata", metadata.Files)
+	}
+	if version.Path != filepath.Join(artifact, "1.1") || version.Scope != types.ScopeVersion || len(version.Files) != 0 {
+		t.Errorf("version result = %+v, want whole 1.1 directory", version)
+	}
+	if want := (types.Coordinates{GroupID: "org.example", ArtifactID: "foo"}); metadata.Coordinates != want {
+		t.Errorf("metadata result Coordinates = %+v, want %+v", metadata.Coordinates, want)
+	}
+	if want := (types.Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.1"}); version.Coordinates != want {
+		t.Errorf("version result Coordinates = %+v, want %+v", version.Coordinates, want)
+	}
+}
+
+func TestScanRepositoryFilter(t *testing.T) {
+	logger := logger.NewCustomLogger()
+
+	root := t.TempDir()
+	versions := map[string]string{
+		"org.apache.maven.plugins:maven-compiler-plugin:3.11.0": filepath.Join(root, "org", "apache", "maven", "plugins", "maven-compiler-plugin", "3.11.0"),
+		"com.mycorp.app:app:1.0":                                filepath.Join(root, "com", "mycorp", "app", "app", "1.0"),
+		"org.example:foo:1.0":                                   filepath.Join(root, "org", "example", "foo", "1.0"),
+	}
+	for key, dir := range versions {
+		parts := strings.Split(key, ":")
+		writeFile(t, filepath.Join(dir, parts[1]+"-"+parts[2]+".pom"), "<project/>")
+		writeFile(t, filepath.Join(dir, parts[1]+"-"+parts[2]+".jar.lastUpdated"), "")
+	}
+
+	tests := []struct {
+		name     string
+		includes []string
+		excludes []string
+		want     []string
+	}{
+		{"no filter", nil, nil, []string{"com.mycorp.app:app:1.0", "org.apache.maven.plugins:maven-compiler-plugin:3.11.0", "org.example:foo:1.0"}},
+		{"exclude plugins", nil, []string{"org.apache.maven.plugins:*"}, []string{"com.mycorp.app:app:1.0", "org.example:foo:1.0"}},
+		{"include company artifacts", []string{"com.mycorp.*:*"}, nil, []string{"com.mycorp.app:app:1.0"}},
+		{"exclude a version", nil, []string{"org.example:foo:1.0"}, []string{"com.mycorp.app:app:1.0", "org.apache.maven.plugins:maven-compiler-plugin:3.11.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := filter.New(tt.includes, tt.excludes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewScanner(logger)
+			s.SetFilter(f)
+			result := s.ScanRepository(types.ScanConfig{InputPath: root, MaxConcurrentGoRoutines: 2})
+
+			var got []string
+			for _, res := range result.Results {
+				got = append(got, res.Coordinates.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ScanRepository() flagged %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsDir(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"org/apache/maven/plugins/maven-compiler-plugin/3.11.0", "org/example/foo/1.0", "com/mycorp/app/app/1.0"} {
+		parts := strings.Split(dir, "/")
+		n := len(parts)
+		writeFile(t, filepath.Join(root, dir, parts[n-2]+"-"+parts[n-1]+".pom"), "<project/>")
+	}
+
+	tests := []struct {
+		name     string
+		includes []string
+		excludes []string
+		dir      string
+		want     bool
+	}{
+		{"no filter", nil, nil, "org/apache", true},
+		{"artifact in excluded group", nil, []string{"org.apache.maven.plugins:*"}, "org/apache/maven/plugins/maven-compiler-plugin", false},
+		// 目录下可能还有子 groupId
+		{"excluded group", nil, []string{"org.apache.maven.plugins:*"}, "org/apache/maven/plugins", true},
+		{"outside included group", []string{"com.mycorp.*:*"}, nil, "org/example", false},
+		{"inside included group", []string{"com.mycorp.*:*"}, nil, "com/mycorp/app", true},
+		{"included artifact", []string{"org.example:foo:*"}, nil, "org/example/foo", true},
+		{"excluded artifact", nil, []string{"org.example:foo:*"}, "org/example/foo", false},
+		{"excluded version", nil, []string{"org.example:foo:1.0"}, "org/example/foo/1.0", false},
+		{"artifact of excluded version", nil, []string{"org.example:foo:1.0"}, "org/example/foo", true},
+		// 各层级结论一致时不读取目录内容，不存在的目录也能判断
+		{"missing directory", []string{"com.mycorp.*:*"}, nil, "net/example/bar", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := filter.New(tt.includes, tt.excludes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewScanner(logger.NewCustomLogger())
+			s.SetFilter(f)
+			if got := s.allowsDir(types.ScanConfig{InputPath: root}, filepath.Join(root, tt.dir)); got != tt.want {
+				t.Errorf("allowsDir(%s) = %v, want %v", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lyj404/clean-mvn/internal/pom"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// timestampedPattern 匹配去掉 "<artifactId>-<基础版本>-" 前缀后的带时间戳快照文件名，
+// 形如 "20260101.120000-42-sources.jar"
+var timestampedPattern = regexp.MustCompile(`^(\d{8}\.\d{6})-(\d+)(.*)$`)
+
+// snapshotBuild 快照目录中的一次带时间戳的构建
+type snapshotBuild struct {
+	timestamp string
+	number    int
+	files     []string // 构件及其校验文件、签名文件
+}
+
+// value 返回构建在元数据中的版本值，如 "1.0-20260101.120000-42"
+func (sb *snapshotBuild) value(base string) string {
+	return fmt.Sprintf("%s-%s-%d", base, sb.timestamp, sb.number)
+}
+
+// SnapshotRetentionDetector 在每个 -SNAPSHOT 目录中按分类器和扩展名保留最新的 N 个带时间戳的构建
+//
+// maven-metadata-*.xml 中记录的当前构建始终保留，其余较旧的构建会被计划删除。
+// 每个快照目录生成一个文件级结果。
+type SnapshotRetentionDetector struct {
+	keep int
+}
+
+// NewSnapshotRetentionDetector 创建快照保留检测器，keep 为每个分类器和扩展名保留的构建数
+func NewSnapshotRetentionDetector(keep int) *SnapshotRetentionDetector {
+	return &SnapshotRetentionDetector{keep: keep}
+}
+
+// Name 返回检测器名称
+func (sd *SnapshotRetentionDetector) Name() string {
+	return "snapshot-retention"
+}
+
+// Match 判断是否为快照版本目录
+func (sd *SnapshotRetentionDetector) Match(path string, d fs.DirEntry) bool {
+	return d.IsDir() && strings.HasSuffix(d.Name(), "-SNAPSHOT")
+}
+
+// Detect 找出超出保留数量的旧快照构建
+func (sd *SnapshotRetentionDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(d.Name(), "-SNAPSHOT")
+	prefix := filepath.Base(filepath.Dir(path)) + "-" + base + "-"
+	current := make(map[string]bool) // 元数据中记录的当前构建，见 currentSnapshotKeys
+
+	// 按 "分类器.扩展名" 分组，再按构建号归并构件及其附属文件
+	groups := make(map[string]map[string]*snapshotBuild)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if isMetadataFile(name) && strings.HasSuffix(name, ".xml") {
+			for _, key := range currentSnapshotKeys(filepath.Join(path, name)) {
+				current[key] = true
+			}
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		match := timestampedPattern.FindStringSubmatch(rest)
+		if match == nil {
+			continue
+		}
+		number, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+
+		key := snapshotGroupKey(match[3])
+		if groups[key] == nil {
+			groups[key] = make(map[string]*snapshotBuild)
+		}
+		id := match[1] + "-" + match[2]
+		build, ok := groups[key][id]
+		if !ok {
+			build = &snapshotBuild{timestamp: match[1], number: number}
+			groups[key][id] = build
+		}
+		build.files = append(build.files, filepath.Join(path, name))
+	}
+
+	var files []string
+	var removed int
+	for key, builds := range groups {
+		sorted := make([]*snapshotBuild, 0, len(builds))
+		for _, build := range builds {
+			sorted = append(sorted, build)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].timestamp != sorted[j].timestamp {
+				return sorted[i].timestamp > sorted[j].timestamp
+			}
+			return sorted[i].number > sorted[j].number
+		})
+
+		for i, build := range sorted {
+			value := build.value(base)
+			if i < sd.keep || current[key+"|"+value] || current["|"+value] {
+				continue
+			}
+			files = append(files, build.files...)
+			removed++
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(files)
+	return []types.Result{{
+		Path:   path,
+		Scope:  types.ScopeVersion,
+		Files:  files,
+		Reason: fmt.Sprintf("%d old snapshot builds beyond the newest %d", removed, sd.keep),
+	}}, nil
+}
+
+// snapshotGroupKey 从时间戳之后的部分提取 "分类器.扩展名"，忽略校验文件和签名文件后缀
+func snapshotGroupKey(suffix string) string {
+	for {
+		ext := filepath.Ext(suffix)
+		if ext == "" || !isChecksumFile(ext) {
+			break
+		}
+		suffix = strings.TrimSuffix(suffix, ext)
+	}
+	return suffix
+}
+
+// currentSnapshotKeys 返回快照元数据中记录的当前构建，格式为 "<分类器.扩展名>|<版本值>"
+//
+// <snapshot> 元素不区分分类器和扩展名，对应的键省略分组部分，对所有分组生效。
+func currentSnapshotKeys(path string) []string {
+	metadata, err := pom.ParseMetadataFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, sv := range metadata.Versioning.SnapshotVersions {
+		group := "." + strings.TrimSpace(sv.Extension)
+		if classifier := strings.TrimSpace(sv.Classifier); classifier != "" {
+			group = "-" + classifier + group
+		}
+		keys = append(keys, group+"|"+strings.TrimSpace(sv.Value))
+	}
+	if snapshot := metadata.Versioning.Snapshot; snapshot.Timestamp != "" {
+		base := strings.TrimSuffix(metadata.Version, "-SNAPSHOT")
+		keys = append(keys, "|"+base+"-"+snapshot.Timestamp+"-"+snapshot.BuildNumber)
+	}
+	return keys
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestSnapshotRetentionDetector(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "org", "example", "foo", "1.0-SNAPSHOT")
+
+	builds := []string{
+		"20260101.120000-1",
+		"20260102.120000-2",
+		"20260103.120000-3",
+		"20260104.120000-4",
+	}
+	for _, build := range builds {
+		writeFile(t, filepath.Join(dir, "foo-1.0-"+build+".jar"), "PK")
+		writeFile(t, filepath.Join(dir, "foo-1.0-"+build+".jar.sha1"), "0")
+		writeFile(t, filepath.Join(dir, "foo-1.0-"+build+".pom"), "<project/>")
+	}
+	// 只有一个 sources 构建，不应被删除
+	writeFile(t, filepath.Join(dir, "foo-1.0-20260101.120000-1-sources.jar"), "PK")
+	// 本地安装的非时间戳快照不受影响
+	writeFile(t, filepath.Join(dir, "foo-1.0-SNAPSHOT.jar"), "PK")
+	// 元数据声明 build 1 的 pom 为当前构建
+	writeFile(t, filepath.Join(dir, "maven-metadata-central.xml"), `<metadata>
+  <groupId>org.example</groupId><artifactId>foo</artifactId><version>1.0-SNAPSHOT</version>
+  <versioning><snapshotVersions>
+    <snapshotVersion><extension>pom</extension><value>1.0-20260101.120000-1</value></snapshotVersion>
+  </snapshotVersions></versioning>
+</metadata>`)
+
+	s := NewScanner(logger.NewCustomLogger(), NewSnapshotRetentionDetector(2))
+	result := s.ScanRepository(scanConfig(root))
+
+	if len(result.Results) != 1 {
+		t.Fatalf("ScanRepository() found %d results, want 1: %v", len(result.Results), result.Results)
+	}
+	res := result.Results[0]
+	if res.Path != dir {
+		t.Errorf("result Path = %s, want %s", res.Path, dir)
+	}
+
+	want := []string{
+		filepath.Join(dir, "foo-1.0-20260101.120000-1.jar"),
+		filepath.Join(dir, "foo-1.0-20260101.120000-1.jar.sha1"),
+		filepath.Join(dir, "foo-1.0-20260102.120000-2.jar"),
+		filepath.Join(dir, "foo-1.0-20260102.120000-2.jar.sha1"),
+		filepath.Join(dir, "foo-1.0-20260102.120000-2.pom"),
+	}
+	if !slices.Equal(res.Files, want) {
+		t.Errorf("result Files = %v, want %v", res.Files, want)
+	}
+}
+
+func TestSnapshotGroupKey(t *testing.T) {
+	tests := []struct {
+		suffix string
+		want   string
+	}{
+		{".jar", ".jar"},
+		{".jar.sha1", ".jar"},
+		{".jar.asc.sha256", ".jar"},
+		{"-sources.jar", "-sources.jar"},
+		{".tar.gz", ".tar.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			if got := snapshotGroupKey(tt.suffix); got != tt.want {
+				t.Errorf("snapshotGroupKey(%q) = %q, want %q", tt.suffix, got, tt.want)
+			}
+		})
+	}
+}
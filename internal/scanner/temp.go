@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// TempDetectorName 临时文件检测器名称，其结果会与其他结果分开汇总
+const TempDetectorName = "temp"
+
+// tempSuffixes 被中断的构建遗留的未完成下载、临时文件和锁文件后缀（aether-*.tmp 也属于 .tmp）
+var tempSuffixes = []string{".part", ".tmp", ".lock"}
+
+// TempDetector 查找被中断的构建遗留的 *.part、*.tmp、aether-*.tmp 和 *.lock 文件
+//
+// 只有修改时间早于 minAge 的文件才会被标记，避免误删仍在进行中的下载。
+type TempDetector struct {
+	minAge time.Duration
+	now    func() time.Time
+}
+
+// NewTempDetector 创建临时文件检测器
+func NewTempDetector(minAge time.Duration) *TempDetector {
+	return &TempDetector{
+		minAge: minAge,
+		now:    time.Now,
+	}
+}
+
+// Name 返回检测器名称
+func (td *TempDetector) Name() string {
+	return TempDetectorName
+}
+
+// Match 判断是否为临时文件
+func (td *TempDetector) Match(path string, d fs.DirEntry) bool {
+	if d.IsDir() {
+		return false
+	}
+	name := strings.ToLower(d.Name())
+	for _, suffix := range tempSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect 标记超过最小存在时间的临时文件，只删除文件本身
+func (td *TempDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	info, err := d.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	age := td.now().Sub(info.ModTime())
+	if age < td.minAge {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(path)
+	return []types.Result{{
+		Path:   dir,
+		Scope:  scopeOf(dir),
+		Files:  []string{path},
+		Reason: fmt.Sprintf("leftover %s, last modified %s ago", d.Name(), age.Round(time.Minute)),
+	}}, nil
+}
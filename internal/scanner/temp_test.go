@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestTempDetector(t *testing.T) {
+	root := t.TempDir()
+	version := filepath.Join(root, "org", "example", "foo", "1.0")
+	old := time.Now().Add(-48 * time.Hour)
+
+	files := map[string]bool{
+		filepath.Join(version, "foo-1.0.jar.part"):        true,
+		filepath.Join(version, "aether-1234abcd.tmp"):     true,
+		filepath.Join(root, ".locks", "artifact.lock"):    true,
+		filepath.Join(version, "foo-1.0.jar"):             false,
+		filepath.Join(version, "foo-1.0.pom.lastUpdated"): false,
+	}
+	for path := range files {
+		writeFile(t, path, "data")
+		os.Chtimes(path, old, old)
+	}
+	fresh := filepath.Join(version, "foo-1.0-sources.jar.part")
+	writeFile(t, fresh, "downloading")
+
+	s := NewScanner(logger.NewCustomLogger(), NewTempDetector(24*time.Hour))
+	result := s.ScanRepository(scanConfig(root))
+
+	var found []string
+	for _, res := range result.Results {
+		if res.Detector != TempDetectorName {
+			t.Errorf("result %s Detector = %q, want %q", res.Path, res.Detector, TempDetectorName)
+		}
+		found = append(found, res.Files...)
+	}
+	if len(found) != 3 {
+		t.Fatalf("ScanRepository() found %d temp files, want 3: %v", len(found), found)
+	}
+	for _, file := range found {
+		if !files[file] {
+			t.Errorf("unexpected temp file %s", file)
+		}
+	}
+	if result.TotalSize != int64(3*len("data")) {
+		t.Errorf("TotalSize = %d, want %d", result.TotalSize, 3*len("data"))
+	}
+}
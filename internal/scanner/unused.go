@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+	"github.com/lyj404/clean-mvn/pkg/types"
+)
+
+// UnusedDetector 标记超过指定时长没有被任何构建读取过的版本目录
+//
+// 目录的最后使用时间取其中所有文件访问时间和修改时间的最大值，
+// 文件系统以 noatime 挂载时访问时间不会更新，此时等同于按修改时间判断。
+type UnusedDetector struct {
+	unusedFor time.Duration
+	now       func() time.Time
+}
+
+// NewUnusedDetector 创建长期未使用检测器
+func NewUnusedDetector(unusedFor time.Duration) *UnusedDetector {
+	return &UnusedDetector{
+		unusedFor: unusedFor,
+		now:       time.Now,
+	}
+}
+
+// Name 返回检测器名称
+func (ud *UnusedDetector) Name() string {
+	return "unused"
+}
+
+// Match 所有目录都可能是版本目录，具体判断在 Detect 中进行
+func (ud *UnusedDetector) Match(path string, d fs.DirEntry) bool {
+	return d.IsDir()
+}
+
+// Detect 计算版本目录的最后使用时间，超过阈值时整体计划删除
+func (ud *UnusedDetector) Detect(root, path string, d fs.DirEntry) ([]types.Result, error) {
+	if !isVersionDir(path) {
+		return nil, nil
+	}
+	lastUsed, _, err := directoryUsage(path)
+	if err != nil || lastUsed.IsZero() {
+		return nil, err
+	}
+
+	idle := ud.now().Sub(lastUsed)
+	if idle < ud.unusedFor {
+		return nil, nil
+	}
+	return []types.Result{{
+		Path:     path,
+		Scope:    types.ScopeVersion,
+		LastUsed: lastUsed,
+		Reason:   fmt.Sprintf("not used for %s (last used %s)", formatIdle(idle), lastUsed.Format("2006-01-02")),
+	}}, nil
+}
+
+// directoryUsage 返回目录中文件的最后使用时间和总大小，目录中没有文件时最后使用时间为零值
+func directoryUsage(dir string) (latest time.Time, size int64, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		size += info.Size()
+		if used := lastUsed(info); used.After(latest) {
+			latest = used
+		}
+	}
+	return latest, size, nil
+}
+
+// lastUsed 返回文件访问时间和修改时间中较新的一个
+func lastUsed(info fs.FileInfo) time.Time {
+	accessed := atime.Of(info)
+	if mtime := info.ModTime(); mtime.After(accessed) {
+		return mtime
+	}
+	return accessed
+}
+
+// formatIdle 将较长的空闲时间格式化为天数
+func formatIdle(idle time.Duration) string {
+	if days := int(idle / (24 * time.Hour)); days > 0 {
+		return fmt.Sprintf("%d days", days)
+	}
+	return idle.Round(time.Minute).String()
+}
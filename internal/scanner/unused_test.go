@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lyj404/clean-mvn/internal/atime"
+	"github.com/lyj404/clean-mvn/internal/logger"
+)
+
+func TestUnusedDetector(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	old := now.Add(-120 * 24 * time.Hour)
+
+	stale := filepath.Join(root, "org", "example", "foo", "1.0")
+	recent := filepath.Join(root, "org", "example", "foo", "2.0")
+	touched := filepath.Join(root, "org", "example", "bar", "1.0")
+	for _, path := range []string{
+		filepath.Join(stale, "foo-1.0.jar"),
+		filepath.Join(stale, "foo-1.0.pom"),
+		filepath.Join(recent, "foo-2.0.jar"),
+		filepath.Join(touched, "bar-1.0.jar"),
+		filepath.Join(touched, "bar-1.0.pom"),
+	} {
+		writeFile(t, path, "data")
+		os.Chtimes(path, old, old)
+	}
+	os.Chtimes(filepath.Join(recent, "foo-2.0.jar"), now, now)
+	// 只读取过 POM 也算被使用
+	os.Chtimes(filepath.Join(touched, "bar-1.0.pom"), now, old)
+
+	s := NewScanner(logger.NewCustomLogger(), NewUnusedDetector(90*24*time.Hour))
+	result := s.ScanRepository(scanConfig(root))
+
+	if len(result.Results) != 1 {
+		t.Fatalf("ScanRepository() returned %d results, want 1: %+v", len(result.Results), result.Results)
+	}
+	res := result.Results[0]
+	if res.Path != stale {
+		t.Errorf("Path = %s, want %s", res.Path, stale)
+	}
+	if len(res.Files) != 0 {
+		t.Errorf("Files = %v, want whole-directory result", res.Files)
+	}
+	if res.LastUsed.Sub(old).Abs() > time.Second {
+		t.Errorf("LastUsed = %v, want %v", res.LastUsed, old)
+	}
+}
+
+func TestLastUsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo-1.0.jar")
+	writeFile(t, path, "data")
+
+	accessed := time.Now().Add(-time.Hour)
+	mtime := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(path, accessed, mtime); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 不支持访问时间的平台上退回修改时间
+	want := accessed
+	if atime.Of(info).Equal(info.ModTime()) {
+		want = mtime
+	}
+	if got := lastUsed(info); got.Sub(want).Abs() > time.Second {
+		t.Errorf("lastUsed() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectorsKeepAccessTime(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-120 * 24 * time.Hour)
+	version := filepath.Join(root, "org", "example", "foo", "1.0")
+	jar := filepath.Join(version, "foo-1.0.jar")
+	writeFile(t, jar, string(buildArchive(t, "a.txt", "a")))
+	writeFile(t, jar+".sha1", "0000000000000000000000000000000000000000")
+	writeFile(t, filepath.Join(version, "foo-1.0.pom"), "<project><groupId>org.example</groupId><artifactId>foo</artifactId><version>1.0</version></project>")
+	writeFile(t, filepath.Join(version, "foo-1.0-sources.jar.lastUpdated"), "central.error=\n")
+	entries, _ := os.ReadDir(version)
+	for _, entry := range entries {
+		os.Chtimes(filepath.Join(version, entry.Name()), old, old)
+	}
+
+	// 读取构件内容的检测器不能让构件看起来刚被使用过
+	s := NewScanner(logger.NewCustomLogger(), NewLastUpdatedDetector(), NewCorruptArchiveDetector(),
+		NewChecksumDetector(), NewContentDetector(), NewPomDetector())
+	s.ScanRepository(scanConfig(root))
+
+	s = NewScanner(logger.NewCustomLogger(), NewUnusedDetector(90*24*time.Hour))
+	result := s.ScanRepository(scanConfig(root))
+	if len(result.Results) != 1 || result.Results[0].Path != version {
+		t.Fatalf("ScanRepository() = %+v, want %s still unused", result.Results, version)
+	}
+}
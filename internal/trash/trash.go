@@ -0,0 +1,463 @@
+// Package trash 管理隔离区：清理时把结果移入按时间戳命名的批次目录，而不是直接删除，之后可以恢复或清空
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// manifestFile 批次目录中记录原始路径的清单文件
+	manifestFile = "manifest.json"
+	// filesDir 批次目录中存放被隔离文件和目录的子目录
+	filesDir = "files"
+	// runIDLayout 批次 id 的时间格式
+	runIDLayout = "20060102-150405"
+)
+
+// rename、removeAll 文件系统操作，测试中替换以模拟跨文件系统移动和删除失败
+var (
+	rename    = os.Rename
+	removeAll = os.RemoveAll
+)
+
+// Entry 隔离区中的一个文件或目录
+type Entry struct {
+	Original string `json:"original"` // 隔离前的绝对路径
+	Stored   string `json:"stored"`   // 相对于批次目录的存放路径
+	Size     int64  `json:"size"`
+	Partial  bool   `json:"partial,omitempty"` // 已复制到隔离区，但原始路径未能完全删除
+	Replace  bool   `json:"replace,omitempty"` // 清理时在原始路径写回了改写后的文件，恢复时替换它
+}
+
+// Manifest 一个隔离批次的清单
+type Manifest struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Entries []Entry   `json:"entries"`
+}
+
+// Size 返回批次中所有文件的总大小
+func (m *Manifest) Size() int64 {
+	var size int64
+	for _, entry := range m.Entries {
+		size += entry.Size
+	}
+	return size
+}
+
+// DefaultDir 返回默认的隔离区目录：CLEAN_MVN_TRASH，或用户主目录下的 .clean-mvn/trash
+func DefaultDir() string {
+	if dir := os.Getenv("CLEAN_MVN_TRASH"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "clean-mvn-trash")
+	}
+	return filepath.Join(home, ".clean-mvn", "trash")
+}
+
+// Run 一次清理对应的隔离批次
+type Run struct {
+	dir      string
+	manifest Manifest
+}
+
+// Begin 在隔离区中创建一个以 now 命名的新批次，同一秒内的批次追加序号
+func Begin(trashDir string, now time.Time) (*Run, error) {
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return nil, err
+	}
+	base := now.Format(runIDLayout)
+	for i := 1; ; i++ {
+		id := base
+		if i > 1 {
+			id += "-" + strconv.Itoa(i)
+		}
+		dir := filepath.Join(trashDir, id)
+		err := os.Mkdir(dir, 0755)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		run := &Run{dir: dir, manifest: Manifest{ID: id, Created: now}}
+		if err := run.save(); err != nil {
+			os.RemoveAll(dir)
+			return nil, err
+		}
+		return run, nil
+	}
+}
+
+// ID 返回批次 id
+func (r *Run) ID() string {
+	return r.manifest.ID
+}
+
+// Dir 返回批次目录
+func (r *Run) Dir() string {
+	return r.dir
+}
+
+// Manifest 返回批次当前的清单
+func (r *Run) Manifest() Manifest {
+	return r.manifest
+}
+
+// Move 把文件或目录移入批次，并立即更新清单，中途退出时已移入的内容仍可恢复
+//
+// 先尝试 os.Rename，失败时（如跨文件系统）复制到批次中，在删除原始路径之前记录一个未完成的条目；
+// 原始路径未能完全删除时条目保持未完成状态，恢复时与原始路径中剩余的内容合并。
+// 路径不存在时返回的错误满足 os.IsNotExist。
+func (r *Run) Move(path string) error {
+	original, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(original); err != nil {
+		return err
+	}
+
+	stored := filepath.Join(filesDir, fmt.Sprintf("%04d-%s", len(r.manifest.Entries)+1, filepath.Base(original)))
+	target := filepath.Join(r.dir, stored)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := rename(original, target); err == nil {
+		r.manifest.Entries = append(r.manifest.Entries, Entry{Original: original, Stored: stored, Size: sizeOf(target)})
+		return r.save()
+	}
+
+	if err := copyTree(original, target, false); err != nil {
+		removeAll(target)
+		return fmt.Errorf("failed to copy %s to the trash: %w", original, err)
+	}
+	r.manifest.Entries = append(r.manifest.Entries, Entry{Original: original, Stored: stored, Size: sizeOf(target), Partial: true})
+	if err := r.save(); err != nil {
+		// 没有记录就不能删除原始路径，撤销复制
+		r.manifest.Entries = r.manifest.Entries[:len(r.manifest.Entries)-1]
+		removeAll(target)
+		return err
+	}
+	if err := removeAll(original); err != nil {
+		return fmt.Errorf("copied %s to trash run %s but failed to remove it: %w", original, r.manifest.ID, err)
+	}
+	r.manifest.Entries[len(r.manifest.Entries)-1].Partial = false
+	return r.save()
+}
+
+// Replace 把文件移入批次，调用方随后会在原始路径写回改写后的内容，恢复时用隔离的原文件替换它
+func (r *Run) Replace(path string) error {
+	if err := r.Move(path); err != nil {
+		return err
+	}
+	r.manifest.Entries[len(r.manifest.Entries)-1].Replace = true
+	return r.save()
+}
+
+// Close 结束批次，没有移入任何内容的批次会被删除
+func (r *Run) Close() error {
+	if len(r.manifest.Entries) == 0 {
+		return os.RemoveAll(r.dir)
+	}
+	return nil
+}
+
+// save 原子地写入清单
+func (r *Run) save() error {
+	return writeManifest(r.dir, r.manifest)
+}
+
+// Runs 返回隔离区中的所有批次，按创建时间排序；隔离区不存在时返回空列表
+func Runs(trashDir string) ([]Manifest, error) {
+	entries, err := os.ReadDir(trashDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Manifest
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		manifest, err := readManifest(filepath.Join(trashDir, entry.Name()))
+		if os.IsNotExist(err) {
+			// 不是批次目录
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, manifest)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].Created.Equal(runs[j].Created) {
+			return runs[i].Created.Before(runs[j].Created)
+		}
+		return runs[i].ID < runs[j].ID
+	})
+	return runs, nil
+}
+
+// Restore 把批次中的内容移回原始路径，paths 为空时恢复整个批次
+//
+// paths 中的路径匹配原始路径相同或位于其下的条目。原始路径已存在的条目不会被覆盖，
+// 保留在批次中并作为错误返回；批次中的条目全部恢复后删除批次目录。
+func Restore(trashDir, id string, paths []string) ([]Entry, error) {
+	if !validRunID(id) {
+		return nil, fmt.Errorf("invalid trash run id %q", id)
+	}
+	dir := filepath.Join(trashDir, id)
+	manifest, err := readManifest(dir)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no trash run %q in %s", id, trashDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var selectors []string
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, abs)
+	}
+
+	var restored []Entry
+	var errs []error
+	matched := make([]bool, len(selectors))
+	kept := manifest.Entries[:0:0]
+	for _, entry := range manifest.Entries {
+		selected := len(selectors) == 0
+		for i, selector := range selectors {
+			if within(entry.Original, selector) {
+				selected, matched[i] = true, true
+			}
+		}
+		if !selected {
+			kept = append(kept, entry)
+			continue
+		}
+		if err := restoreEntry(dir, entry); err != nil {
+			errs = append(errs, err)
+			kept = append(kept, entry)
+			continue
+		}
+		restored = append(restored, entry)
+	}
+	for i, selector := range selectors {
+		if !matched[i] {
+			errs = append(errs, fmt.Errorf("%s is not in trash run %s", selector, id))
+		}
+	}
+
+	manifest.Entries = kept
+	if len(kept) == 0 {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+		}
+	} else if len(restored) > 0 {
+		if err := writeManifest(dir, manifest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return restored, errors.Join(errs...)
+}
+
+// Purge 删除创建时间早于 now - olderThan 的批次，返回被删除的批次
+func Purge(trashDir string, olderThan time.Duration, now time.Time) ([]Manifest, error) {
+	runs, err := Runs(trashDir)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := now.Add(-olderThan)
+
+	var purged []Manifest
+	var errs []error
+	for _, run := range runs {
+		if run.Created.After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(trashDir, run.ID)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		purged = append(purged, run)
+	}
+	return purged, errors.Join(errs...)
+}
+
+// restoreEntry 把一个条目移回原始路径，原始路径已存在时返回错误
+//
+// 未完成的条目与原始路径中剩余的内容合并，只补回缺失的文件；替换条目用原文件覆盖清理时写回的文件。
+func restoreEntry(dir string, entry Entry) error {
+	stored := filepath.Join(dir, entry.Stored)
+	if entry.Replace {
+		if info, err := os.Lstat(entry.Original); err == nil {
+			if !info.Mode().IsRegular() {
+				return fmt.Errorf("%s is not a regular file, not overwriting it", entry.Original)
+			}
+			if err := os.Remove(entry.Original); err != nil {
+				return err
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if entry.Partial {
+		if err := os.MkdirAll(filepath.Dir(entry.Original), 0755); err != nil {
+			return err
+		}
+		if err := copyTree(stored, entry.Original, true); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", entry.Original, err)
+		}
+		removeAll(stored)
+		return nil
+	}
+
+	if _, err := os.Lstat(entry.Original); err == nil {
+		return fmt.Errorf("%s already exists, not overwriting it", entry.Original)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.Original), 0755); err != nil {
+		return err
+	}
+	if err := rename(stored, entry.Original); err == nil {
+		return nil
+	}
+	if err := copyTree(stored, entry.Original, false); err != nil {
+		removeAll(entry.Original)
+		return fmt.Errorf("failed to restore %s: %w", entry.Original, err)
+	}
+	// 内容已经恢复，隔离区中的副本随批次目录一起删除
+	removeAll(stored)
+	return nil
+}
+
+// validRunID 判断批次 id 是否为隔离区中的单层目录名，拒绝 ".."、路径分隔符等可能指向隔离区之外的 id
+func validRunID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`) && filepath.Base(id) == id
+}
+
+// within 判断 path 是否为 parent 本身或位于其下
+func within(path, parent string) bool {
+	rel, err := filepath.Rel(parent, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// copyTree 递归复制文件或目录，保留权限、修改时间和符号链接；merge 为 true 时跳过目标中已存在的文件
+func copyTree(src, dst string, merge bool) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if merge && !d.IsDir() {
+			if _, err := os.Lstat(target); err == nil {
+				return nil
+			}
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			if err := copyFile(path, target, info.Mode().Perm()); err != nil {
+				return err
+			}
+			// 保留修改时间，按使用时间清理的规则依赖它
+			return os.Chtimes(target, info.ModTime(), info.ModTime())
+		}
+	})
+}
+
+// copyFile 复制单个文件
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// sizeOf 返回文件或目录中所有文件的总大小
+func sizeOf(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// readManifest 读取批次目录中的清单
+func readManifest(dir string) (Manifest, error) {
+	var manifest Manifest
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("invalid trash manifest in %s: %w", dir, err)
+	}
+	return manifest, nil
+}
+
+// writeManifest 先写入临时文件再重命名，避免中断时留下不完整的清单
+func writeManifest(dir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, manifestFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestFile))
+}
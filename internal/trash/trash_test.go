@@ -0,0 +1,345 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func TestRunMoveAndRestore(t *testing.T) {
+	repo := t.TempDir()
+	trashDir := filepath.Join(t.TempDir(), "trash")
+	version := filepath.Join(repo, "org", "example", "foo", "1.0")
+	marker := filepath.Join(repo, "org", "example", "bar", "2.0", "bar-2.0.jar.lastUpdated")
+	writeFile(t, filepath.Join(version, "foo-1.0.jar"), "PK")
+	writeFile(t, marker, "test")
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	run, err := Begin(trashDir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.ID() != "20260102-030405" {
+		t.Errorf("Begin() ID = %s, want 20260102-030405", run.ID())
+	}
+	for _, path := range []string{version, marker} {
+		if err := run.Move(path); err != nil {
+			t.Fatalf("Move(%s) error = %v", path, err)
+		}
+		if exists(path) {
+			t.Errorf("Move(%s) left the original in place", path)
+		}
+	}
+	if err := run.Move(filepath.Join(repo, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Move(missing) error = %v, want not exist", err)
+	}
+	if err := run.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := Runs(trashDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || len(runs[0].Entries) != 2 || runs[0].Size() != int64(len("PK")+len("test")) {
+		t.Fatalf("Runs() = %+v, want one run with both entries", runs)
+	}
+
+	restored, err := Restore(trashDir, run.ID(), nil)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(restored) != 2 {
+		t.Errorf("Restore() restored %d entries, want 2", len(restored))
+	}
+	for _, path := range []string{filepath.Join(version, "foo-1.0.jar"), marker} {
+		if !exists(path) {
+			t.Errorf("Restore() did not put back %s", path)
+		}
+	}
+	if exists(run.Dir()) {
+		t.Errorf("Restore() kept the emptied run %s", run.Dir())
+	}
+}
+
+func TestRestoreSelectedPaths(t *testing.T) {
+	repo := t.TempDir()
+	trashDir := t.TempDir()
+	foo := filepath.Join(repo, "org", "example", "foo", "1.0")
+	bar := filepath.Join(repo, "org", "example", "bar", "1.0")
+	baz := filepath.Join(repo, "com", "example", "baz", "1.0")
+	for _, dir := range []string{foo, bar, baz} {
+		writeFile(t, filepath.Join(dir, "a.jar"), "PK")
+	}
+
+	run, err := Begin(trashDir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{foo, bar, baz} {
+		if err := run.Move(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 原始路径被重新下载的目录占用时不覆盖
+	writeFile(t, filepath.Join(bar, "a.jar"), "new")
+
+	restored, err := Restore(trashDir, run.ID(), []string{filepath.Join(repo, "org"), filepath.Join(repo, "net")})
+	if err == nil {
+		t.Error("Restore() error = nil, want conflict and unmatched path errors")
+	}
+	var got []string
+	for _, entry := range restored {
+		got = append(got, entry.Original)
+	}
+	if !slices.Equal(got, []string{foo}) {
+		t.Errorf("Restore() restored %v, want %v", got, []string{foo})
+	}
+	if data, _ := os.ReadFile(filepath.Join(bar, "a.jar")); string(data) != "new" {
+		t.Errorf("Restore() overwrote %s", bar)
+	}
+
+	runs, err := Runs(trashDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, entry := range runs[0].Entries {
+		left = append(left, entry.Original)
+	}
+	if !slices.Equal(left, []string{bar, baz}) {
+		t.Errorf("manifest after Restore() = %v, want %v", left, []string{bar, baz})
+	}
+
+	if _, err := Restore(trashDir, "19700101-000000", nil); err == nil {
+		t.Error("Restore() of an unknown run should fail")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	trashDir := t.TempDir()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	source := t.TempDir()
+
+	var ids []string
+	for _, age := range []time.Duration{40 * 24 * time.Hour, 10 * 24 * time.Hour, time.Hour} {
+		run, err := Begin(trashDir, now.Add(-age))
+		if err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(source, run.ID())
+		writeFile(t, file, "test")
+		if err := run.Move(file); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, run.ID())
+	}
+	// 不是批次的目录保持不变
+	os.Mkdir(filepath.Join(trashDir, "other"), 0755)
+
+	tests := []struct {
+		name      string
+		olderThan time.Duration
+		want      []string
+	}{
+		{"older than 30 days", 30 * 24 * time.Hour, ids[:1]},
+		{"older than 1 day", 24 * time.Hour, ids[1:2]},
+		{"everything", 0, ids[2:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purged, err := Purge(trashDir, tt.olderThan, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, run := range purged {
+				got = append(got, run.ID)
+				if exists(filepath.Join(trashDir, run.ID)) {
+					t.Errorf("Purge() left %s", run.ID)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Purge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !exists(filepath.Join(trashDir, "other")) {
+		t.Error("Purge() removed a directory that is not a trash run")
+	}
+}
+
+func TestBeginSameSecond(t *testing.T) {
+	trashDir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first, err := Begin(trashDir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Begin(trashDir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID() == second.ID() || second.ID() != first.ID()+"-2" {
+		t.Errorf("Begin() IDs = %s, %s, want distinct runs", first.ID(), second.ID())
+	}
+	second.Close()
+	if exists(second.Dir()) {
+		t.Error("Close() kept an empty run")
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "1.0")
+	jar := filepath.Join(src, "foo-1.0.jar")
+	writeFile(t, jar, "PK")
+	writeFile(t, filepath.Join(src, "nested", "foo.pom"), "<project/>")
+	if err := os.Symlink("foo-1.0.jar", filepath.Join(src, "link.jar")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	old := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	os.Chtimes(jar, old, old)
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	if err := copyTree(src, dst, false); err != nil {
+		t.Fatalf("copyTree() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "nested", "foo.pom")); string(data) != "<project/>" {
+		t.Errorf("copyTree() nested file = %q", data)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "foo-1.0.jar")); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("copyTree() did not keep the modification time: %v", err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "link.jar")); err != nil || link != "foo-1.0.jar" {
+		t.Errorf("copyTree() link = %q, %v, want foo-1.0.jar", link, err)
+	}
+}
+
+func TestMoveCopyFallback(t *testing.T) {
+	// 模拟跨文件系统：重命名总是失败
+	defer func(r func(string, string) error) { rename = r }(rename)
+	rename = func(string, string) error {
+		return &os.LinkError{Op: "rename", Err: errors.New("invalid cross-device link")}
+	}
+
+	repo := t.TempDir()
+	version := filepath.Join(repo, "org", "example", "foo", "1.0")
+	jar := filepath.Join(version, "foo-1.0.jar")
+	pom := filepath.Join(version, "foo-1.0.pom")
+	writeFile(t, jar, "PK")
+	writeFile(t, pom, "<project/>")
+	other := filepath.Join(repo, "org", "example", "bar", "1.0")
+	writeFile(t, filepath.Join(other, "bar-1.0.jar"), "PK")
+
+	trashDir := t.TempDir()
+	run, err := Begin(trashDir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run.Move(other); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if exists(other) || run.Manifest().Entries[0].Partial {
+		t.Errorf("Move() by copy should remove %s and complete the entry", other)
+	}
+
+	// 删除原始路径时只删掉了一部分
+	defer func(r func(string) error) { removeAll = r }(removeAll)
+	removeAll = func(path string) error {
+		if path == version {
+			os.Remove(jar)
+			return errors.New("permission denied")
+		}
+		return os.RemoveAll(path)
+	}
+	if err := run.Move(version); err == nil {
+		t.Fatal("Move() error = nil, want the removal failure")
+	}
+	removeAll = os.RemoveAll
+
+	runs, err := Runs(trashDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := runs[0].Entries
+	if len(entries) != 2 || entries[1].Original != version || !entries[1].Partial {
+		t.Fatalf("manifest entries = %+v, want a partial entry for %s", entries, version)
+	}
+
+	restored, err := Restore(trashDir, run.ID(), []string{version})
+	if err != nil || len(restored) != 1 {
+		t.Fatalf("Restore() = %v, %v, want the partial entry restored", restored, err)
+	}
+	for _, path := range []string{jar, pom} {
+		if !exists(path) {
+			t.Errorf("Restore() did not put back %s", path)
+		}
+	}
+}
+
+func TestRestoreInvalidRunID(t *testing.T) {
+	base := t.TempDir()
+	trashDir := filepath.Join(base, "trash")
+	// 隔离区之外看起来像批次的目录
+	outside := filepath.Join(base, "x")
+	writeFile(t, filepath.Join(outside, manifestFile), `{"id":"x","entries":[]}`)
+
+	for _, id := range []string{"", ".", "..", "../x", "a/b", `a\b`} {
+		if _, err := Restore(trashDir, id, nil); err == nil || !strings.Contains(err.Error(), "invalid trash run id") {
+			t.Errorf("Restore(%q) error = %v, want invalid id", id, err)
+		}
+	}
+	if !exists(outside) {
+		t.Errorf("Restore() removed %s outside the trash", outside)
+	}
+}
+
+func TestReplaceAndRestore(t *testing.T) {
+	repo := t.TempDir()
+	status := filepath.Join(repo, "org", "example", "foo", "resolver-status.properties")
+	writeFile(t, status, "original")
+
+	trashDir := t.TempDir()
+	run, err := Begin(trashDir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run.Replace(status); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if entries := run.Manifest().Entries; len(entries) != 1 || !entries[0].Replace {
+		t.Fatalf("manifest entries = %+v, want one replace entry", entries)
+	}
+	// 清理时在原位置写回改写后的内容
+	writeFile(t, status, "rewritten")
+
+	restored, err := Restore(trashDir, run.ID(), nil)
+	if err != nil || len(restored) != 1 {
+		t.Fatalf("Restore() = %v, %v, want the replace entry restored", restored, err)
+	}
+	if data, _ := os.ReadFile(status); string(data) != "original" {
+		t.Errorf("Restore() left %q, want the original file", data)
+	}
+	if exists(run.Dir()) {
+		t.Errorf("Restore() kept the emptied run %s", run.Dir())
+	}
+}
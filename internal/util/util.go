@@ -4,10 +4,15 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/lyj404/clean-mvn/internal/check"
 	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/internal/resolver"
+	"github.com/lyj404/clean-mvn/internal/scanner"
+	"github.com/lyj404/clean-mvn/internal/trash"
 	"github.com/lyj404/clean-mvn/pkg/types"
 )
 
@@ -35,10 +40,315 @@ func ValidatePath(logger *logger.CustomLogger, path string) bool {
 func DisplayScanResults(logger *logger.CustomLogger, result types.ScanResult) {
 	logger.Time("Scan completed, took %s.", time.Duration(result.Duration*int64(time.Millisecond)).Round(time.Millisecond))
 
-	if len(result.Results) > 0 {
-		logger.Info("Found %d unique directories containing '.lastUpdated' files, total %.2f MB to be deleted.",
-			len(result.Results), float64(result.TotalSize)/1024/1024)
+	flagged, leftovers := SplitLeftovers(result.Results)
+
+	if len(flagged) > 0 {
+		var flaggedSize int64
+		for _, res := range flagged {
+			flaggedSize += res.Size
+		}
+		logger.Info("Found %d unique directories flagged for cleanup, total %.2f MB to be deleted.",
+			len(flagged), float64(flaggedSize)/1024/1024)
+
+		// 按检测器分组统计
+		counts := make(map[string]int)
+		sizes := make(map[string]int64)
+		for _, res := range flagged {
+			counts[res.Detector]++
+			sizes[res.Detector] += res.Size
+		}
+		detectors := make([]string, 0, len(counts))
+		for detector := range counts {
+			detectors = append(detectors, detector)
+		}
+		sort.Strings(detectors)
+		for _, detector := range detectors {
+			logger.Info("  %s: %d directories, %.2f MB", detector, counts[detector], float64(sizes[detector])/1024/1024)
+		}
+	}
+
+	if len(leftovers) > 0 {
+		var files int
+		var leftoverSize int64
+		for _, res := range leftovers {
+			files += len(res.Files)
+			leftoverSize += res.Size
+		}
+		logger.Info("Found %d leftover temp and lock files in %d directories, total %.2f MB to be deleted.",
+			files, len(leftovers), float64(leftoverSize)/1024/1024)
+	}
+
+	if affected := CountDependents(result.Results); affected > 0 {
+		logger.Warning("%d other cached artifacts depend on the flagged artifacts.", affected)
+	}
+
+	if len(result.Roots) > 1 {
+		displayRoots(logger, result)
+	} else if result.SizeBudget > 0 {
+		logger.Info("Repository size: %.2f MB, projected after cleanup: %.2f MB (budget %.2f MB).",
+			float64(result.RepositorySize)/1024/1024, float64(result.ProjectedSize)/1024/1024,
+			float64(result.SizeBudget)/1024/1024)
+		if result.ProjectedSize > result.SizeBudget {
+			logger.Warning("The repository cannot be shrunk below the size budget, %.2f MB over after evicting every version directory.",
+				float64(result.ProjectedSize-result.SizeBudget)/1024/1024)
+		}
+	}
+
+	repositories := GroupFailuresByRepository(result.Results)
+	if len(repositories) > 0 {
+		logger.Info("Download failures by remote repository:")
+		for _, repo := range repositories {
+			logger.Warning("  %s: %d failed downloads, last attempt %s", repo.Repository, repo.Count,
+				repo.LastAttempt.Format("2006-01-02 15:04:05"))
+			if repo.LastError != "" {
+				logger.Info("    %s", truncate(repo.LastError, 160))
+			}
+		}
+	}
+}
+
+// displayRoots 显示每个仓库根目录的统计和总计，大小预算按仓库（包括分离布局中的所有子树）分别显示
+func displayRoots(logger *logger.CustomLogger, result types.ScanResult) {
+	logger.Info("Results by repository:")
+	for _, root := range result.Roots {
+		if root.Error != nil {
+			logger.Warning("  %s: scan incomplete: %v", root.Root, root.Error)
+		}
+		logger.Info("  %s: %d directories, %.2f MB", rootLabel(root), root.Count, float64(root.TotalSize)/1024/1024)
+	}
+	logger.Info("  total: %d directories, %.2f MB in %d repositories", len(result.Results),
+		float64(result.TotalSize)/1024/1024, len(result.Roots))
+
+	if len(result.Repositories) > 0 {
+		logger.Info("Size budget by repository (all trees of a split repository share one budget):")
+		for _, repo := range result.Repositories {
+			logger.Info("  %s: size %.2f MB, projected after cleanup %.2f MB (budget %.2f MB)", repo.Root,
+				float64(repo.RepositorySize)/1024/1024, float64(repo.ProjectedSize)/1024/1024,
+				float64(result.SizeBudget)/1024/1024)
+			if repo.ProjectedSize > result.SizeBudget {
+				logger.Warning("    cannot be shrunk below the size budget, %.2f MB over after evicting every version directory",
+					float64(repo.ProjectedSize-result.SizeBudget)/1024/1024)
+			}
+		}
+	}
+
+	byRepository := GroupRootsByRepositoryID(result.Roots)
+	if len(byRepository) > 0 {
+		logger.Info("Results by remote repository:")
+		for _, repo := range byRepository {
+			logger.Info("  %s: %d directories, %.2f MB", repo.Root, repo.Count, float64(repo.TotalSize)/1024/1024)
+		}
+	}
+}
+
+// rootLabel 返回根目录的显示名称，分离布局中的子树附带其类型和远程仓库 id，非 Maven 仓库附带仓库类型
+func rootLabel(root types.RootSummary) string {
+	switch {
+	case root.Tree.Type != types.RepositoryMaven:
+		return fmt.Sprintf("%s (%s)", root.Root, root.Tree.Type)
+	case root.Tree.RepositoryID != "":
+		return fmt.Sprintf("%s (%s, %s)", root.Root, root.Tree.Kind, root.Tree.RepositoryID)
+	case root.Tree.Kind != types.TreeFlat:
+		return fmt.Sprintf("%s (%s)", root.Root, root.Tree.Kind)
+	}
+	return root.Root
+}
+
+// GroupRootsByRepositoryID 按远程仓库 id 汇总分离布局中各子树的统计，按 id 排序
+//
+// 只有按远程仓库分离的子树才有 id，没有这样的子树时返回空切片。汇总结果的 Root 为远程仓库 id。
+func GroupRootsByRepositoryID(roots []types.RootSummary) []types.RootSummary {
+	groups := make(map[string]*types.RootSummary)
+	for _, root := range roots {
+		id := root.Tree.RepositoryID
+		if id == "" {
+			continue
+		}
+		group, ok := groups[id]
+		if !ok {
+			group = &types.RootSummary{Root: id, Tree: types.Tree{Kind: types.TreeCached, RepositoryID: id}}
+			groups[id] = group
+		}
+		group.Count += root.Count
+		group.TotalSize += root.TotalSize
+		group.RepositorySize += root.RepositorySize
+		group.ProjectedSize += root.ProjectedSize
+	}
+
+	summaries := make([]types.RootSummary, 0, len(groups))
+	for _, group := range groups {
+		summaries = append(summaries, *group)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Root < summaries[j].Root
+	})
+	return summaries
+}
+
+// SplitLeftovers 将只由临时文件检测器标记的结果与其他结果分开
+func SplitLeftovers(results []types.Result) (flagged, leftovers []types.Result) {
+	for _, res := range results {
+		if res.Detector == scanner.TempDetectorName {
+			leftovers = append(leftovers, res)
+		} else {
+			flagged = append(flagged, res)
+		}
+	}
+	return flagged, leftovers
+}
+
+// DisplayResultDetails 逐条显示扫描结果，用于预览模式
+func DisplayResultDetails(logger *logger.CustomLogger, results []types.Result) {
+	for _, res := range results {
+		target := res.Path
+		if res.Coordinates.GroupID != "" {
+			target = fmt.Sprintf("%s (%s)", res.Coordinates, res.Path)
+		}
+		if len(res.Files) > 0 {
+			target = fmt.Sprintf("%s, %d files", target, len(res.Files))
+		}
+		if !res.LastUsed.IsZero() {
+			target += ", last used " + res.LastUsed.Format("2006-01-02")
+		}
+		logger.Info("  [%s] %s, %.2f MB", res.Detector, target, float64(res.Size)/1024/1024)
+		if res.Reason != "" {
+			logger.Info("    %s", truncate(res.Reason, 160))
+		}
+		if len(res.Dependents) > 0 || len(res.TransitiveDependents) > 0 {
+			logger.Info("    impact: %d direct, %d transitive dependents: %s", len(res.Dependents), len(res.TransitiveDependents),
+				truncate(strings.Join(append(append([]string(nil), res.Dependents...), res.TransitiveDependents...), ", "), 160))
+		}
+		if len(res.UnversionedDependents) > 0 {
+			logger.Info("    may also be used by %d artifacts that declare it without a resolved version (not cascaded): %s",
+				len(res.UnversionedDependents), truncate(strings.Join(res.UnversionedDependents, ", "), 160))
+		}
+	}
+}
+
+// DisplayMissingChecksums 列出没有任何校验文件的构件
+func DisplayMissingChecksums(logger *logger.CustomLogger, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	logger.Warning("%d artifacts have no checksum file (.sha1/.md5/.sha256/.sha512):", len(paths))
+	for _, path := range paths {
+		logger.Info("  %s", path)
+	}
+}
+
+// DisplayClosureProblems 列出依赖闭包中无法从本地仓库解析的构件及需要它们的依赖路径
+func DisplayClosureProblems(logger *logger.CustomLogger, problems []resolver.Problem) {
+	if len(problems) == 0 {
+		return
+	}
+	logger.Warning("%d artifacts in the dependency closure could not be resolved from the local repository:", len(problems))
+	for _, problem := range problems {
+		logger.Warning("  %s: %s", problem.Artifact, problem.Reason)
+		logger.Info("    via %s", strings.Join(problem.Path, " -> "))
+	}
+}
+
+// DisplayCheckReport 显示离线完整性检查结果，逐条列出无法离线使用的构件及需要它们的依赖路径
+func DisplayCheckReport(logger *logger.CustomLogger, report *check.Report) {
+	if report.Complete() {
+		logger.Success("All %d artifacts in the dependency closure of %d projects are available offline.",
+			report.Checked, len(report.Projects))
+		return
+	}
+
+	logger.Error("The dependency closure of %d projects is incomplete: %d problems found.",
+		len(report.Projects), len(report.Issues))
+	for _, issue := range report.Issues {
+		logger.Warning("  [%s] %s: %s", issue.Status, issue.Artifact, truncate(issue.Detail, 160))
+		logger.Info("    via %s", strings.Join(issue.Path, " -> "))
+	}
+}
+
+// DisplayTrashRuns 列出隔离区中的批次及其条目数和大小
+func DisplayTrashRuns(logger *logger.CustomLogger, trashDir string, runs []trash.Manifest) {
+	if len(runs) == 0 {
+		logger.Info("The trash in %s is empty.", trashDir)
+		return
+	}
+	logger.Info("Trash runs in %s:", trashDir)
+	for _, run := range runs {
+		logger.Info("  %s  %s  %d entries, %.2f MB", run.ID, run.Created.Local().Format("2006-01-02 15:04:05"),
+			len(run.Entries), float64(run.Size())/1024/1024)
+	}
+}
+
+// CountDependents 统计直接或间接依赖结果的本地构件数，同一构件只计一次
+func CountDependents(results []types.Result) int {
+	dependents := make(map[string]bool)
+	for _, res := range results {
+		for _, dependent := range append(append([]string(nil), res.Dependents...), res.TransitiveDependents...) {
+			dependents[dependent] = true
+		}
+	}
+	return len(dependents)
+}
+
+// RepositoryFailures 某个远程仓库的下载失败汇总
+type RepositoryFailures struct {
+	Repository  string
+	Count       int
+	LastAttempt time.Time
+	LastError   string // 最近一次失败的错误信息
+}
+
+// GroupFailuresByRepository 按远程仓库汇总扫描结果中的下载失败，失败次数多的仓库排在前面
+func GroupFailuresByRepository(results []types.Result) []RepositoryFailures {
+	groups := make(map[string]*RepositoryFailures)
+	for _, res := range results {
+		for _, failure := range res.Failures {
+			group, ok := groups[failure.Repository]
+			if !ok {
+				group = &RepositoryFailures{Repository: failure.Repository}
+				groups[failure.Repository] = group
+			}
+			group.Count++
+			if !failure.LastAttempt.Before(group.LastAttempt) {
+				group.LastAttempt = failure.LastAttempt
+				if failure.Error != "" {
+					group.LastError = failure.Error
+				}
+			} else if group.LastError == "" {
+				group.LastError = failure.Error
+			}
+		}
+	}
+
+	repositories := make([]RepositoryFailures, 0, len(groups))
+	for _, group := range groups {
+		repositories = append(repositories, *group)
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		if repositories[i].Count != repositories[j].Count {
+			return repositories[i].Count > repositories[j].Count
+		}
+		return repositories[i].Repository < repositories[j].Repository
+	})
+	return repositories
+}
+
+// CountMarkers 统计扫描结果中的下载失败标记数量，与只删除标记模式实际删除的数量一致
+func CountMarkers(results []types.Result) int {
+	count := 0
+	for _, res := range results {
+		for _, marker := range res.Markers {
+			count += scanner.MarkerCount(marker)
+		}
+	}
+	return count
+}
+
+// truncate 截断过长的文本
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= max {
+		return s
 	}
+	return string([]rune(s)[:max]) + "..."
 }
 
 // GetUserConfirmation 获取用户确认
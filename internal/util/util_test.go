@@ -4,8 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/lyj404/clean-mvn/internal/cleaner"
 	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/pkg/types"
 )
 
 func TestValidatePath(t *testing.T) {
@@ -78,3 +81,92 @@ func TestGetDefaultPath(t *testing.T) {
 		_ = expectedPath
 	})
 }
+
+func TestGroupFailuresByRepository(t *testing.T) {
+	central := "https://repo.maven.apache.org/maven2/"
+	mirror := "https://mirror.example.com/maven/"
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	results := []types.Result{
+		{Path: "/a", Failures: []types.Failure{
+			{Repository: central, Error: "old error", LastAttempt: older},
+			{Repository: mirror, Error: "mirror error", LastAttempt: older},
+		}},
+		{Path: "/b", Failures: []types.Failure{
+			{Repository: central, Error: "new error", LastAttempt: newer},
+		}},
+		{Path: "/c"},
+	}
+
+	got := GroupFailuresByRepository(results)
+	if len(got) != 2 {
+		t.Fatalf("GroupFailuresByRepository() returned %d groups, want 2", len(got))
+	}
+	if got[0].Repository != central || got[0].Count != 2 {
+		t.Errorf("got[0] = %+v, want %s with 2 failures", got[0], central)
+	}
+	if got[0].LastError != "new error" || !got[0].LastAttempt.Equal(newer) {
+		t.Errorf("got[0] last failure = %q at %v, want %q at %v", got[0].LastError, got[0].LastAttempt, "new error", newer)
+	}
+	if got[1].Repository != mirror || got[1].Count != 1 {
+		t.Errorf("got[1] = %+v, want %s with 1 failure", got[1], mirror)
+	}
+}
+
+func TestGroupRootsByRepositoryID(t *testing.T) {
+	roots := []types.RootSummary{
+		{Root: "/a/installed", Tree: types.Tree{Kind: types.TreeInstalled}, Count: 5, TotalSize: 50},
+		{Root: "/a/cached/central", Tree: types.Tree{Kind: types.TreeCached, RepositoryID: "central"}, Count: 1, TotalSize: 10},
+		{Root: "/b/cached/central", Tree: types.Tree{Kind: types.TreeCached, RepositoryID: "central"}, Count: 2, TotalSize: 20},
+		{Root: "/a/cached/corp", Tree: types.Tree{Kind: types.TreeCached, RepositoryID: "corp"}, Count: 3, TotalSize: 30},
+	}
+
+	got := GroupRootsByRepositoryID(roots)
+	if len(got) != 2 {
+		t.Fatalf("GroupRootsByRepositoryID() returned %d groups, want 2", len(got))
+	}
+	if got[0].Root != "central" || got[0].Count != 3 || got[0].TotalSize != 30 {
+		t.Errorf("got[0] = %+v, want central with 3 results and 30 bytes", got[0])
+	}
+	if got[1].Root != "corp" || got[1].Count != 3 || got[1].TotalSize != 30 {
+		t.Errorf("got[1] = %+v, want corp with 3 results and 30 bytes", got[1])
+	}
+
+	if got := GroupRootsByRepositoryID(roots[:1]); len(got) != 0 {
+		t.Errorf("GroupRootsByRepositoryID() without repository ids = %+v, want none", got)
+	}
+}
+
+func TestSplitLeftovers(t *testing.T) {
+	results := []types.Result{
+		{Path: "/a", Detector: "lastupdated"},
+		{Path: "/b", Detector: "temp", Files: []string{"/b/foo.part"}},
+		{Path: "/c", Detector: "lastupdated,temp"},
+	}
+
+	flagged, leftovers := SplitLeftovers(results)
+	if len(flagged) != 2 || len(leftovers) != 1 || leftovers[0].Path != "/b" {
+		t.Errorf("SplitLeftovers() = %v, %v", flagged, leftovers)
+	}
+}
+
+func TestCountMarkersMatchesClean(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "foo-1.0.jar.lastUpdated")
+	status := filepath.Join(dir, "resolver-status.properties")
+	os.WriteFile(marker, []byte("central.error=\n"), 0644)
+	os.WriteFile(status, []byte("maven-metadata-central.xml/central.error=Could not transfer\n"+
+		"maven-metadata-central.xml/central.lastUpdated=1700000000000\n"+
+		"maven-metadata-corp.xml/corp.error=Unauthorized\n"+
+		"maven-metadata-nexus.xml/nexus.lastUpdated=1700000000000\n"), 0644)
+	results := []types.Result{{Path: dir, Markers: []string{marker, status}}}
+
+	// 预览和实际清理对同一棵树报告相同的数量
+	want := CountMarkers(results)
+	c := cleaner.NewCleaner(logger.NewCustomLogger())
+	c.SetMode(cleaner.ModeMarkers)
+	if got := c.CleanDirectories(results).MarkersRemoved; got != want || want != 3 {
+		t.Errorf("CountMarkers() = %d, MarkersRemoved = %d, want both 3", want, got)
+	}
+}
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"os"
 	"runtime"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/lyj404/clean-mvn/internal/check"
 	"github.com/lyj404/clean-mvn/internal/cleaner"
 	"github.com/lyj404/clean-mvn/internal/cli"
+	"github.com/lyj404/clean-mvn/internal/filter"
 	"github.com/lyj404/clean-mvn/internal/logger"
+	"github.com/lyj404/clean-mvn/internal/repository"
+	"github.com/lyj404/clean-mvn/internal/resolver"
 	"github.com/lyj404/clean-mvn/internal/scanner"
+	"github.com/lyj404/clean-mvn/internal/trash"
 	"github.com/lyj404/clean-mvn/internal/util"
 	"github.com/lyj404/clean-mvn/pkg/types"
 )
@@ -29,28 +38,54 @@ func main() {
 		}
 	}
 
-	// 获取输入路径
-	inputPath := config.Path
-	if inputPath == "" {
-		inputPath = util.GetUserInput()
+	switch config.Command {
+	case cli.CommandCheck:
+		os.Exit(runCheck(loggerInstance, config))
+	case cli.CommandRestore:
+		os.Exit(runRestore(loggerInstance, config))
+	case cli.CommandPurgeTrash:
+		os.Exit(runPurgeTrash(loggerInstance, config))
+	}
+
+	// 获取仓库路径
+	roots, err := cli.ExpandPaths(config.Paths)
+	if err != nil {
+		loggerInstance.Error("%v", err)
+		return
+	}
+	if len(roots) == 0 {
+		inputPath := util.GetUserInput()
 		if inputPath == "" {
-			inputPath = cli.GetDefaultPath()
+			var source string
+			inputPath, source = cli.DiscoverRepository()
+			if inputPath != "" {
+				loggerInstance.Info("Using Maven repository %s (from %s)", inputPath, source)
+			}
+		}
+		if inputPath != "" {
+			roots = []string{inputPath}
 		}
 	}
 
 	// 验证路径
-	if inputPath == "" {
+	if len(roots) == 0 {
 		loggerInstance.Error("No path specified. Use --path to specify the Maven repository path.")
 		cli.ShowUsage()
 		return
 	}
 
-	if !util.ValidatePath(loggerInstance, inputPath) {
-		return
+	for _, root := range roots {
+		if !util.ValidatePath(loggerInstance, root) {
+			return
+		}
 	}
 
 	// 开始扫描
-	loggerInstance.Info("Starting scan of Maven repository path: %s", inputPath)
+	if len(roots) == 1 {
+		loggerInstance.Info("Starting scan of Maven repository path: %s", roots[0])
+	} else {
+		loggerInstance.Info("Starting scan of %d Maven repositories: %s", len(roots), strings.Join(roots, ", "))
+	}
 
 	// 确定并发工作数
 	workers := config.Workers
@@ -61,14 +96,76 @@ func main() {
 		workers = runtime.NumCPU()
 	}
 
-	// 创建扫描器并执行扫描
-	s := scanner.NewScanner(loggerInstance)
-	scanConfig := types.ScanConfig{
-		InputPath:               inputPath,
-		MaxConcurrentGoRoutines: workers,
+	coordinateFilter, err := filter.New(config.Includes, config.Excludes)
+	if err != nil {
+		loggerInstance.Error("%v", err)
+		return
 	}
 
-	scanResult := s.ScanRepository(scanConfig)
+	// 每个仓库（分离布局中的每棵子树）使用独立的扫描器，并发扫描
+	var scanners []*scanner.Scanner
+	var scanConfigs []types.ScanConfig
+	for _, root := range roots {
+		trees := repository.Trees(root)
+		var closure *resolver.Closure
+		if len(config.Projects) > 0 && trees[0].Type == types.RepositoryMaven {
+			closure, err = resolver.NewResolver(root).Resolve(config.Projects)
+			if err != nil {
+				loggerInstance.Error("Failed to read project: %v", err)
+				return
+			}
+			loggerInstance.Info("Resolved the dependency closure of %d projects against %s: %d artifacts.",
+				len(closure.Projects), root, len(closure.Nodes))
+			util.DisplayClosureProblems(loggerInstance, closure.Problems)
+		}
+
+		// 反向依赖索引覆盖整个仓库，各子树共用，避免每棵子树重复解析所有 POM
+		var index *resolver.ReverseIndex
+		if (config.Impact || config.Cascade) && trees[0].Type == types.RepositoryMaven {
+			index, err = resolver.NewResolver(root).ReverseIndex()
+			if err != nil {
+				loggerInstance.Warning("Failed to build the reverse dependency index for %s: %v (impact analysis skipped)", root, err)
+			}
+		}
+
+		switch {
+		case trees[0].Type == types.RepositoryGradle:
+			loggerInstance.Info("Detected the Gradle module cache in %s.", trees[0].Path)
+		case trees[0].Type == types.RepositoryIvy:
+			loggerInstance.Info("Detected the Ivy cache in %s.", trees[0].Path)
+		case repository.IsSplit(root):
+			loggerInstance.Info("Detected the split local repository layout in %s: %d trees.", root, len(trees))
+		}
+		// 跳过的 installed/ 子树不会被清理，但仍占用仓库的大小预算
+		var protected []string
+		for _, tree := range trees {
+			if tree.Kind == types.TreeInstalled && !config.IncludeInstalled {
+				loggerInstance.Info("Skipping locally installed artifacts in %s (use --include-installed to clean them).", tree.Path)
+				protected = append(protected, tree.Path)
+			}
+		}
+		for _, tree := range trees {
+			if slices.Contains(protected, tree.Path) {
+				continue
+			}
+			s, scanConfig, err := newTreeScanner(loggerInstance, config, root, tree, workers, closure, index)
+			if err != nil {
+				loggerInstance.Error("%v", err)
+				return
+			}
+			scanConfig.ProtectedTrees = protected
+			s.SetFilter(coordinateFilter)
+			scanners = append(scanners, s)
+			scanConfigs = append(scanConfigs, scanConfig)
+		}
+	}
+
+	if len(scanners) == 0 {
+		loggerInstance.Info("Nothing to scan.")
+		return
+	}
+
+	scanResult := scanner.ScanRoots(scanners, scanConfigs)
 
 	// 处理扫描结果
 	if scanResult.Error != nil {
@@ -77,15 +174,22 @@ func main() {
 
 	// 显示扫描结果
 	util.DisplayScanResults(loggerInstance, scanResult)
+	util.DisplayMissingChecksums(loggerInstance, scanResult.MissingChecksums)
 
 	// 如果没有找到文件，退出
 	if len(scanResult.Results) == 0 {
-		loggerInstance.Success("Congratulations! No '.lastUpdated' related build directories found in your Maven repository.")
+		loggerInstance.Success("Congratulations! No directories flagged for cleanup found in your Maven repository.")
 		return
 	}
 
 	// 预览模式
 	if config.DryRun {
+		util.DisplayResultDetails(loggerInstance, scanResult.Results)
+		if config.MarkersOnly {
+			loggerInstance.Info("Dry run mode: Would remove %d failure markers in %d directories.",
+				util.CountMarkers(scanResult.Results), len(scanResult.Results))
+			return
+		}
 		loggerInstance.Info("Dry run mode: Would delete %d directories, freeing %.2f MB space.",
 			len(scanResult.Results), float64(scanResult.TotalSize)/1024/1024)
 		return
@@ -99,9 +203,184 @@ func main() {
 
 	// 执行清理
 	cleanerInstance := cleaner.NewCleaner(loggerInstance)
+	cleanerInstance.SetFilter(coordinateFilter)
+	if config.MarkersOnly {
+		cleanerInstance.SetMode(cleaner.ModeMarkers)
+	}
+	var run *trash.Run
+	if config.Quarantine {
+		run, err = trash.Begin(trashDirOf(config), time.Now())
+		if err != nil {
+			loggerInstance.Error("Failed to create trash run: %v", err)
+			return
+		}
+		cleanerInstance.SetTrash(run)
+	}
 	cleanResult := cleanerInstance.CleanDirectories(scanResult.Results)
 
 	// 显示清理结果
+	if run != nil {
+		if err := run.Close(); err != nil {
+			loggerInstance.Warning("Failed to close trash run %s: %v", run.Dir(), err)
+		}
+		if len(run.Manifest().Entries) > 0 {
+			restore := "clean-mvn restore"
+			if config.TrashDir != "" {
+				restore += " --trash-dir " + config.TrashDir
+			}
+			loggerInstance.Info("Moved %d paths to trash run %s; undo with: %s %s",
+				len(run.Manifest().Entries), run.Dir(), restore, run.ID())
+		}
+	}
+	if config.MarkersOnly {
+		loggerInstance.Success("Cleanup complete! Removed %d failure markers in %d directories.",
+			cleanResult.MarkersRemoved, cleanResult.DeletedCount)
+		return
+	}
+	if run != nil {
+		loggerInstance.Success("Cleanup complete! Moved %d directories (%.2f MB) to the trash.",
+			cleanResult.DeletedCount, float64(cleanResult.DeletedSize)/1024/1024)
+		return
+	}
 	loggerInstance.Success("Cleanup complete! Deleted %d directories, freed %.2f MB space.",
 		cleanResult.DeletedCount, float64(cleanResult.DeletedSize)/1024/1024)
 }
+
+// newTreeScanner 为仓库中的一棵目录树创建扫描配置和扫描器，检测器的状态不在目录树之间共享
+//
+// index 为整个仓库的反向依赖索引，为 nil 时不做影响分析。
+func newTreeScanner(logger *logger.CustomLogger, config cli.Config, root string, tree types.Tree, workers int, closure *resolver.Closure, index *resolver.ReverseIndex) (*scanner.Scanner, types.ScanConfig, error) {
+	scanConfig := types.ScanConfig{
+		InputPath:               tree.Path,
+		MaxConcurrentGoRoutines: workers,
+		TempMinAge:              config.TempMinAge,
+		KeepSnapshots:           config.KeepSnapshots,
+		KeepReleases:            config.KeepReleases,
+		UnusedFor:               config.UnusedFor,
+		MaxSize:                 config.MaxSize,
+		Impact:                  config.Impact,
+		Cascade:                 config.Cascade,
+		Tree:                    tree,
+		RepositoryRoot:          root,
+	}
+	if tree.Type != types.RepositoryMaven {
+		// 保留策略、大小预算和影响分析依赖 Maven 仓库的布局和 POM
+		if scanConfig.KeepSnapshots > 0 || scanConfig.KeepReleases > 0 || scanConfig.UnusedFor > 0 ||
+			scanConfig.MaxSize > 0 || scanConfig.Impact || scanConfig.Cascade {
+			logger.Warning("Retention, size budget and impact options only apply to Maven repositories; ignoring them for %s.", tree.Path)
+		}
+		scanConfig.KeepSnapshots, scanConfig.KeepReleases, scanConfig.UnusedFor, scanConfig.MaxSize = 0, 0, 0, 0
+		scanConfig.Impact, scanConfig.Cascade = false, false
+	}
+	if index == nil {
+		scanConfig.Impact, scanConfig.Cascade = false, false
+	}
+	detectors, err := scanner.NewDetectors(config.Detectors, scanConfig)
+	if err != nil {
+		return nil, scanConfig, err
+	}
+	if closure != nil {
+		detectors = append(detectors, scanner.NewProjectDetector(closure))
+	}
+	s := scanner.NewScanner(logger, detectors...)
+	s.SetReverseIndex(index)
+	return s, scanConfig, nil
+}
+
+// runCheck 执行 check 命令，依赖闭包不完整时返回 1，无法完成检查时返回 2
+func runCheck(logger *logger.CustomLogger, config cli.Config) int {
+	paths, err := cli.ExpandPaths(config.Paths)
+	if err != nil {
+		logger.Error("%v", err)
+		return 2
+	}
+	if len(paths) > 1 {
+		logger.Error("The check command takes a single repository, got %d: %s", len(paths), strings.Join(paths, ", "))
+		return 2
+	}
+
+	var repo string
+	if len(paths) == 1 {
+		repo = paths[0]
+	} else {
+		var source string
+		repo, source = cli.DiscoverRepository()
+		if repo != "" {
+			logger.Info("Using Maven repository %s (from %s)", repo, source)
+		}
+	}
+	if !util.ValidatePath(logger, repo) {
+		return 2
+	}
+
+	projects := config.Projects
+	if len(projects) == 0 {
+		projects = []string{"."}
+	}
+
+	logger.Info("Checking whether %s can be built offline from %s", strings.Join(projects, ", "), repo)
+	report, err := check.Run(repo, projects)
+	if err != nil {
+		logger.Error("Failed to read project: %v", err)
+		return 2
+	}
+
+	util.DisplayCheckReport(logger, report)
+	if !report.Complete() {
+		return 1
+	}
+	return 0
+}
+
+// trashDirOf 返回配置的隔离区目录，未指定时使用默认目录
+func trashDirOf(config cli.Config) string {
+	if config.TrashDir != "" {
+		return config.TrashDir
+	}
+	return trash.DefaultDir()
+}
+
+// runRestore 执行 restore 命令：未指定批次时列出隔离区中的批次，否则把批次中的内容移回原始路径，有条目无法恢复时返回 1
+func runRestore(logger *logger.CustomLogger, config cli.Config) int {
+	trashDir := trashDirOf(config)
+	if config.TrashRun == "" {
+		runs, err := trash.Runs(trashDir)
+		if err != nil {
+			logger.Error("Failed to read the trash: %v", err)
+			return 2
+		}
+		util.DisplayTrashRuns(logger, trashDir, runs)
+		return 0
+	}
+
+	restored, err := trash.Restore(trashDir, config.TrashRun, config.RestorePaths)
+	for _, entry := range restored {
+		logger.Info("Restored %s", entry.Original)
+	}
+	if err != nil {
+		logger.Error("Failed to restore from trash run %s: %v", config.TrashRun, err)
+		return 1
+	}
+	logger.Success("Restored %d paths from trash run %s.", len(restored), config.TrashRun)
+	return 0
+}
+
+// runPurgeTrash 执行 purge-trash 命令，删除早于 --older-than 的隔离批次
+func runPurgeTrash(logger *logger.CustomLogger, config cli.Config) int {
+	if config.OlderThan < 0 {
+		logger.Error("The purge-trash command requires --older-than, e.g. --older-than 30d (0 empties the whole trash).")
+		return 2
+	}
+
+	purged, err := trash.Purge(trashDirOf(config), config.OlderThan, time.Now())
+	var size int64
+	for _, run := range purged {
+		size += run.Size()
+	}
+	if err != nil {
+		logger.Error("Failed to purge the trash: %v", err)
+		return 1
+	}
+	logger.Success("Purged %d trash runs, freed %.2f MB space.", len(purged), float64(size)/1024/1024)
+	return 0
+}
@@ -0,0 +1,217 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Coordinates 根据仓库中的路径推断出的 Maven 坐标
+type Coordinates struct {
+	GroupID    string
+	ArtifactID string // groupId 层级的结果为空
+	Version    string // groupId、artifactId 层级的结果为空；快照为目录名，如 1.0-SNAPSHOT
+	Classifier string // 只在路径指向单个构件文件时填写
+	Extension  string // 只在路径指向单个构件文件时填写，如 jar、pom、jar.sha1
+}
+
+// String 返回 groupId:artifactId:extension[:classifier]:version 形式的坐标，省略为空的部分
+func (c Coordinates) String() string {
+	parts := []string{c.GroupID}
+	if c.ArtifactID != "" {
+		parts = append(parts, c.ArtifactID)
+	}
+	if c.Extension != "" {
+		parts = append(parts, c.Extension)
+		if c.Classifier != "" {
+			parts = append(parts, c.Classifier)
+		}
+	}
+	if c.Version != "" {
+		parts = append(parts, c.Version)
+	}
+	return strings.Join(parts, ":")
+}
+
+var (
+	// idPattern groupId 的每一段和 artifactId 允许的字符，与 Maven 的模型校验一致
+	idPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+	// snapshotTimestamp 带时间戳的快照文件名中 "<artifactId>-<基础版本>-" 之后的部分，如 20260101.120000-1
+	snapshotTimestamp = regexp.MustCompile(`^\d{8}\.\d{6}-\d+`)
+	// sha1Dir Gradle 模块缓存中以文件 SHA-1 命名的目录，Gradle 会省略摘要开头的 0
+	sha1Dir = regexp.MustCompile(`^[0-9a-f]{1,40}$`)
+)
+
+// gradleDepth Gradle 模块缓存中各层级目录相对于缓存根目录的深度
+var gradleDepth = map[Scope]int{ScopeGroup: 1, ScopeArtifact: 2, ScopeVersion: 3, ScopeFile: 4}
+
+// invalidVersionChars Maven 不允许在版本号中出现的字符
+const invalidVersionChars = `\/:"<>|?* `
+
+// ParseCoordinates 根据相对于仓库根目录的目录路径推断坐标
+//
+// 路径按 "<groupId 路径>/<artifactId>/<version>" 解析，scope 决定路径指向哪一层；
+// 层级不足或某一段不是合法的 groupId、artifactId、版本号时返回错误。
+func ParseCoordinates(rel string, scope Scope) (Coordinates, error) {
+	segments, err := splitPath(rel)
+	if err != nil {
+		return Coordinates{}, err
+	}
+
+	depth := map[Scope]int{ScopeGroup: 1, ScopeArtifact: 2, ScopeVersion: 3}[scope]
+	if depth == 0 {
+		return Coordinates{}, fmt.Errorf("unknown scope %q", scope)
+	}
+	if len(segments) < depth {
+		return Coordinates{}, fmt.Errorf("%s is too shallow for a %s directory", rel, scope)
+	}
+
+	var c Coordinates
+	n := len(segments)
+	switch scope {
+	case ScopeVersion:
+		c.Version = segments[n-1]
+		if strings.ContainsAny(c.Version, invalidVersionChars) {
+			return Coordinates{}, fmt.Errorf("invalid version %q in %s", c.Version, rel)
+		}
+		segments = segments[:n-1]
+		fallthrough
+	case ScopeArtifact:
+		n = len(segments)
+		c.ArtifactID = segments[n-1]
+		if !idPattern.MatchString(c.ArtifactID) {
+			return Coordinates{}, fmt.Errorf("invalid artifactId %q in %s", c.ArtifactID, rel)
+		}
+		segments = segments[:n-1]
+	}
+	for _, segment := range segments {
+		if !idPattern.MatchString(segment) {
+			return Coordinates{}, fmt.Errorf("invalid groupId segment %q in %s", segment, rel)
+		}
+	}
+	c.GroupID = strings.Join(segments, ".")
+	return c, nil
+}
+
+// ParseArtifactPath 根据相对于仓库根目录的构件文件路径推断坐标，包括分类器和扩展名
+//
+// 文件名应为 "<artifactId>-<version>[-<classifier>].<extension>"，快照版本也可以是带时间戳的
+// "<artifactId>-<基础版本>-<时间戳>-<构建号>..."；分类器不含点，扩展名为第一个点之后的全部内容。
+func ParseArtifactPath(rel string) (Coordinates, error) {
+	segments, err := splitPath(rel)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	if len(segments) < 4 {
+		return Coordinates{}, fmt.Errorf("%s is too shallow for an artifact file", rel)
+	}
+	name := segments[len(segments)-1]
+	c, err := ParseCoordinates(strings.Join(segments[:len(segments)-1], "/"), ScopeVersion)
+	if err != nil {
+		return Coordinates{}, err
+	}
+
+	rest, ok := strings.CutPrefix(name, c.ArtifactID+"-"+c.Version)
+	if base, snapshot := strings.CutSuffix(c.Version, "-SNAPSHOT"); !ok && snapshot {
+		if rest, ok = strings.CutPrefix(name, c.ArtifactID+"-"+base+"-"); ok {
+			timestamp := snapshotTimestamp.FindString(rest)
+			ok = timestamp != ""
+			rest = strings.TrimPrefix(rest, timestamp)
+		}
+	}
+	if !ok {
+		return Coordinates{}, fmt.Errorf("file name %s does not start with %s-%s", name, c.ArtifactID, c.Version)
+	}
+
+	if classifier, ok := strings.CutPrefix(rest, "-"); ok {
+		c.Classifier, c.Extension, _ = strings.Cut(classifier, ".")
+		if c.Classifier == "" {
+			return Coordinates{}, fmt.Errorf("file name %s has an empty classifier", name)
+		}
+	} else if c.Extension, ok = strings.CutPrefix(rest, "."); !ok {
+		return Coordinates{}, fmt.Errorf("file name %s does not match %s-%s", name, c.ArtifactID, c.Version)
+	}
+	if c.Extension == "" {
+		return Coordinates{}, fmt.Errorf("file name %s has no extension", name)
+	}
+	return c, nil
+}
+
+// ParseGradleCoordinates 根据相对于 Gradle 模块缓存（files-2.1）的目录路径推断坐标
+//
+// 路径按 "<group>/<module>/<version>/<sha1>" 解析，group 为单层目录，层级必须与 scope 一致。
+// scope 为 ScopeFile 且 file 为 SHA-1 目录中的文件名时，同时解析分类器和扩展名。
+func ParseGradleCoordinates(rel string, scope Scope, file string) (Coordinates, error) {
+	segments, err := splitPath(rel)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	depth := gradleDepth[scope]
+	if depth == 0 {
+		return Coordinates{}, fmt.Errorf("unknown scope %q", scope)
+	}
+	if len(segments) != depth {
+		return Coordinates{}, fmt.Errorf("%s is not a %s directory of a Gradle module cache", rel, scope)
+	}
+
+	if scope != ScopeFile {
+		return ParseCoordinates(rel, scope)
+	}
+	if hash := segments[3]; !sha1Dir.MatchString(hash) {
+		return Coordinates{}, fmt.Errorf("invalid SHA-1 directory %q in %s", hash, rel)
+	}
+	c, err := ParseCoordinates(strings.Join(segments[:3], "/"), ScopeVersion)
+	if err != nil || file == "" {
+		return c, err
+	}
+	if withFile, err := ParseArtifactPath(strings.Join(append(segments[:3:3], file), "/")); err == nil {
+		c = withFile
+	}
+	return c, nil
+}
+
+// ParseIvyCoordinates 根据相对于 Ivy 缓存的目录路径和版本推断坐标
+//
+// 路径按 "<organisation>/<module>" 解析，两者都是单层目录；sbt 插件的模块目录下还有 scala_<版本>、sbt_<版本> 目录。
+// revision 非空时作为版本号，scope 为 ScopeGroup 时路径只能有一层。
+func ParseIvyCoordinates(rel string, scope Scope, revision string) (Coordinates, error) {
+	segments, err := splitPath(rel)
+	if err != nil {
+		return Coordinates{}, err
+	}
+	switch {
+	case scope == ScopeGroup && len(segments) == 1:
+		return ParseCoordinates(rel, ScopeGroup)
+	case scope != ScopeArtifact || len(segments) < 2:
+		return Coordinates{}, fmt.Errorf("%s is not a %s directory of an Ivy cache", rel, scope)
+	}
+	for _, segment := range segments[2:] {
+		if !strings.HasPrefix(segment, "scala_") && !strings.HasPrefix(segment, "sbt_") {
+			return Coordinates{}, fmt.Errorf("unexpected directory %q in %s", segment, rel)
+		}
+	}
+
+	c, err := ParseCoordinates(strings.Join(segments[:2], "/"), ScopeArtifact)
+	if err != nil || revision == "" {
+		return c, err
+	}
+	if strings.ContainsAny(revision, invalidVersionChars) {
+		return Coordinates{}, fmt.Errorf("invalid revision %q in %s", revision, rel)
+	}
+	c.Version = revision
+	return c, nil
+}
+
+// splitPath 将相对路径按 "/" 或 "\" 拆分为路径段，拒绝空路径、空段和 "." ".." 段
+func splitPath(rel string) ([]string, error) {
+	segments := strings.FieldsFunc(rel, func(r rune) bool { return r == '/' || r == '\\' })
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	for _, segment := range segments {
+		if segment == "." || segment == ".." {
+			return nil, fmt.Errorf("%s is outside the repository", rel)
+		}
+	}
+	return segments, nil
+}
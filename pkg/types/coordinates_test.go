@@ -0,0 +1,138 @@
+package types
+
+import "testing"
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		rel     string
+		scope   Scope
+		want    Coordinates
+		wantErr bool
+	}{
+		{"org/example/foo/1.0", ScopeVersion, Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.0"}, false},
+		{`org\example\foo\1.0-SNAPSHOT`, ScopeVersion, Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.0-SNAPSHOT"}, false},
+		{"org/example/foo", ScopeArtifact, Coordinates{GroupID: "org.example", ArtifactID: "foo"}, false},
+		{"org/example", ScopeGroup, Coordinates{GroupID: "org.example"}, false},
+		{"foo/1.0", ScopeVersion, Coordinates{}, true},
+		{"org/example/foo/1.0", "", Coordinates{}, true},
+		{"org/ex ample/foo/1.0", ScopeVersion, Coordinates{}, true},
+		{"org/example/foo/1:0", ScopeVersion, Coordinates{}, true},
+		{"../example/foo/1.0", ScopeVersion, Coordinates{}, true},
+		{".", ScopeGroup, Coordinates{}, true},
+		{"", ScopeGroup, Coordinates{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCoordinates(tt.rel, tt.scope)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCoordinates(%q, %q) error = %v, wantErr %v", tt.rel, tt.scope, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCoordinates(%q, %q) = %+v, want %+v", tt.rel, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestParseArtifactPath(t *testing.T) {
+	tests := []struct {
+		rel     string
+		want    Coordinates
+		wantErr bool
+	}{
+		{"org/example/foo/1.0/foo-1.0.jar", Coordinates{"org.example", "foo", "1.0", "", "jar"}, false},
+		{"org/example/foo/1.0/foo-1.0-sources.jar", Coordinates{"org.example", "foo", "1.0", "sources", "jar"}, false},
+		{"org/example/foo/1.0/foo-1.0.tar.gz", Coordinates{"org.example", "foo", "1.0", "", "tar.gz"}, false},
+		{"org/example/foo/1.0/foo-1.0.jar.sha1", Coordinates{"org.example", "foo", "1.0", "", "jar.sha1"}, false},
+		{"org/example/foo/1.0-SNAPSHOT/foo-1.0-20260101.120000-3-tests.jar", Coordinates{"org.example", "foo", "1.0-SNAPSHOT", "tests", "jar"}, false},
+		{"org/example/foo/1.0-SNAPSHOT/foo-1.0-SNAPSHOT.pom", Coordinates{"org.example", "foo", "1.0-SNAPSHOT", "", "pom"}, false},
+		{"org/example/foo/1.0/bar-1.0.jar", Coordinates{}, true},
+		{"org/example/foo/1.0/foo-1.0", Coordinates{}, true},
+		{"org/example/foo/1.0/foo-1.0-.jar", Coordinates{}, true},
+		{"org/example/foo/1.0/foo-1.0-sources", Coordinates{}, true},
+		{"org/example/foo/1.0/foo-1.0x.jar", Coordinates{}, true},
+		{"org/example/foo/1.0-SNAPSHOT/foo-1.0-latest.jar", Coordinates{}, true},
+		{"foo/1.0/foo-1.0.jar", Coordinates{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseArtifactPath(tt.rel)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseArtifactPath(%q) error = %v, wantErr %v", tt.rel, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseArtifactPath(%q) = %+v, want %+v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestParseGradleCoordinates(t *testing.T) {
+	tests := []struct {
+		rel     string
+		scope   Scope
+		file    string
+		want    Coordinates
+		wantErr bool
+	}{
+		{"org.example", ScopeGroup, "", Coordinates{GroupID: "org.example"}, false},
+		{"org.example/foo/1.0", ScopeVersion, "", Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.0"}, false},
+		{"org.example/foo/1.0/a94a8fe5ccb19ba61c4c0873d391e987982fbbd3", ScopeFile, "foo-1.0-sources.jar", Coordinates{"org.example", "foo", "1.0", "sources", "jar"}, false},
+		{"org.example/foo/1.0/4a8fe5", ScopeFile, "renamed.jar", Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.0"}, false},
+		{"org/example/foo/1.0", ScopeVersion, "", Coordinates{}, true},
+		{"org.example/foo/1.0/not-a-hash", ScopeFile, "", Coordinates{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGradleCoordinates(tt.rel, tt.scope, tt.file)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseGradleCoordinates(%q, %q) error = %v, wantErr %v", tt.rel, tt.scope, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseGradleCoordinates(%q, %q) = %+v, want %+v", tt.rel, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestParseIvyCoordinates(t *testing.T) {
+	tests := []struct {
+		rel      string
+		scope    Scope
+		revision string
+		want     Coordinates
+		wantErr  bool
+	}{
+		{"org.example", ScopeGroup, "", Coordinates{GroupID: "org.example"}, false},
+		{"org.example/foo", ScopeArtifact, "", Coordinates{GroupID: "org.example", ArtifactID: "foo"}, false},
+		{"org.example/foo", ScopeArtifact, "1.0", Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.0"}, false},
+		{"org.example/sbt-plugin/scala_2.12/sbt_1.0", ScopeArtifact, "0.4", Coordinates{GroupID: "org.example", ArtifactID: "sbt-plugin", Version: "0.4"}, false},
+		{"org.example/foo/jars", ScopeArtifact, "1.0", Coordinates{}, true},
+		{"org.example/foo", ScopeArtifact, "1:0", Coordinates{}, true},
+		{"org.example/foo", ScopeVersion, "1.0", Coordinates{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseIvyCoordinates(tt.rel, tt.scope, tt.revision)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseIvyCoordinates(%q, %q, %q) error = %v, wantErr %v", tt.rel, tt.scope, tt.revision, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseIvyCoordinates(%q, %q, %q) = %+v, want %+v", tt.rel, tt.scope, tt.revision, got, tt.want)
+		}
+	}
+}
+
+func TestCoordinatesString(t *testing.T) {
+	tests := []struct {
+		c    Coordinates
+		want string
+	}{
+		{Coordinates{GroupID: "org.example"}, "org.example"},
+		{Coordinates{GroupID: "org.example", ArtifactID: "foo"}, "org.example:foo"},
+		{Coordinates{GroupID: "org.example", ArtifactID: "foo", Version: "1.0"}, "org.example:foo:1.0"},
+		{Coordinates{"org.example", "foo", "1.0", "sources", "jar"}, "org.example:foo:jar:sources:1.0"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
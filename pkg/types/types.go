@@ -1,21 +1,119 @@
 package types
 
+import "time"
+
+// Scope 结果所在的仓库层级
+type Scope string
+
+const (
+	ScopeGroup    Scope = "group"    // groupId 目录
+	ScopeArtifact Scope = "artifact" // artifactId 目录
+	ScopeVersion  Scope = "version"  // 版本目录
+	ScopeFile     Scope = "file"     // Gradle 模块缓存中以 SHA-1 命名、存放单个文件的目录
+)
+
+// RepositoryType 仓库的类型
+type RepositoryType string
+
+const (
+	RepositoryMaven  RepositoryType = ""       // Maven 本地仓库
+	RepositoryGradle RepositoryType = "gradle" // Gradle 模块缓存（caches/modules-2/files-2.1）
+	RepositoryIvy    RepositoryType = "ivy"    // Ivy 缓存（~/.ivy2/cache）
+)
+
+// TreeKind Maven 3.9 分离布局中子树的类型
+type TreeKind string
+
+const (
+	TreeFlat      TreeKind = ""          // 未分离的仓库
+	TreeInstalled TreeKind = "installed" // 本地安装的构件
+	TreeCached    TreeKind = "cached"    // 从远程仓库下载的构件
+)
+
+// Tree 本地仓库中按 "<groupId 路径>/<artifactId>/<version>" 组织构件的一棵目录树
+//
+// Gradle 模块缓存按 "<group>/<module>/<version>/<sha1>/<文件>" 组织，group 为带点的单层目录；
+// Ivy 缓存按 "<organisation>/<module>/" 组织，同一模块所有版本的文件都在模块目录中。
+type Tree struct {
+	Path         string
+	Kind         TreeKind
+	RepositoryID string         // 按远程仓库分离时子树对应的远程仓库 id
+	Type         RepositoryType // 目录树的仓库类型
+}
+
 // Result 用于存储找到的需要删除的目录信息
 type Result struct {
-	Path string
-	Size int64
+	Root        string // 结果所在的仓库根目录
+	Path        string
+	Coordinates Coordinates // 根据 Path 相对于 Root 的路径推断出的坐标
+	Size        int64
+	Detector    string             // 标记该目录的检测器名称
+	Failures    []Failure          // 从 .lastUpdated 文件中解析出的下载失败记录
+	Scope       Scope              // Path 所在的仓库层级
+	Files       []string           // 非空时只删除这些文件，而不是整个 Path 目录
+	Markers     []string           // 目录中的下载失败标记文件（.lastUpdated、resolver-status.properties）
+	Reason      string             // 检测器给出的标记原因
+	Mismatches  []ChecksumMismatch // 与校验文件不一致的构件摘要
+	ContentType string             // 内容嗅探识别出的实际文件类型
+	LastUsed    time.Time          // 目录中文件最后一次被访问或修改的时间
+
+	Dependents            []string // 直接依赖该构件的本地构件（groupId:artifactId:version）
+	TransitiveDependents  []string // 间接依赖该构件的本地构件，不含直接依赖
+	UnversionedDependents []string // 声明依赖该构件但无法确定版本的本地构件，只报告，不级联删除
+}
+
+// ChecksumMismatch 构件的实际摘要与 .sha1/.md5 等校验文件中记录的不一致
+type ChecksumMismatch struct {
+	File      string // 构件文件路径
+	Algorithm string // 摘要算法，如 sha1、sha256
+	Expected  string // 校验文件中记录的摘要
+	Actual    string // 实际计算出的摘要
+}
+
+// Failure .lastUpdated 文件中记录的一次下载失败
+type Failure struct {
+	Repository  string    // 远程仓库地址
+	Error       string    // 缓存的错误信息
+	LastAttempt time.Time // 最后一次尝试下载的时间
 }
 
 // ScanConfig 扫描配置
 type ScanConfig struct {
 	InputPath               string
 	MaxConcurrentGoRoutines int
+	TempMinAge              time.Duration // 临时文件和 Gradle 空 SHA-1 目录的最小存在时间，更新的可能仍在下载中
+	KeepSnapshots           int           // 每个快照目录按分类器和扩展名保留的构建数，0 表示不启用快照保留
+	KeepReleases            int           // 每个构件保留的正式版本数，0 表示不启用旧版本清理
+	UnusedFor               time.Duration // 版本目录超过该时长未被使用时计划删除，0 表示不启用
+	MaxSize                 int64         // 仓库的目标大小（字节），超出时按最近最少使用的顺序删除版本目录，0 表示不启用
+	Impact                  bool          // 是否分析损坏构件对其他本地构件的影响
+	Cascade                 bool          // 是否同时删除依赖损坏构件的本地构件，隐含 Impact
+	Tree                    Tree          // InputPath 在分离布局中对应的子树
+	RepositoryRoot          string        // 整个本地仓库的根目录，用于解析跨子树的依赖关系，为空时使用 InputPath
+	ProtectedTrees          []string      // 同一仓库中不扫描、但计入大小预算的子树，如默认跳过的 installed/
 }
 
 // ScanResult 扫描结果
 type ScanResult struct {
-	Results   []Result
-	TotalSize int64
-	Duration  int64 // 毫秒
-	Error     error
+	Results          []Result
+	TotalSize        int64
+	Duration         int64 // 毫秒
+	Error            error
+	MissingChecksums []string      // 没有任何校验文件的构件
+	RepositorySize   int64         // 仓库的总大小，只在设置了大小预算时统计
+	ProjectedSize    int64         // 删除所有结果后仓库的预计大小
+	SizeBudget       int64         // 仓库的目标大小，0 表示未设置
+	Roots            []RootSummary // 同时扫描多个仓库根目录时，每个根目录的统计
+	Repositories     []RootSummary // 设置了大小预算时，每个仓库（分离布局中包括所有子树）的预算统计
+}
+
+// RootSummary 同时扫描多个仓库根目录时，单个根目录的统计
+type RootSummary struct {
+	Root           string
+	Tree           Tree  // 分离布局中根目录对应的子树
+	Count          int   // 需要清理的结果数
+	TotalSize      int64 // 需要清理的总大小
+	RepositorySize int64 // 仓库的总大小，只在设置了大小预算时统计
+	ProjectedSize  int64 // 删除所有结果后仓库的预计大小
+	Error          error
 }